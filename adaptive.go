@@ -0,0 +1,111 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+AdaptiveBackOff is a backoff policy that adapts its delay to the observed
+success rate using additive-increase/multiplicative-decrease (AIMD) on the
+request rate: every failure multiplies the delay by Multiplier and every
+success subtracts Step from it. The delay is kept between MinInterval and
+MaxInterval.
+
+NextBackOff() is called by the Retry functions after a failed operation, so
+it returns the current delay and records a failure. Call Success() when an
+operation succeeds and Failure() for failures observed outside of NextBackOff().
+
+AdaptiveBackOff is meant to be shared by many callers, so that they converge
+on a rate the dependency can sustain. For the same reason Reset() does not
+discard the learned delay.
+
+AdaptiveBackOff is safe for concurrent use.
+*/
+type AdaptiveBackOff struct {
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// Step is subtracted from the delay on success. It is also the delay
+	// used after a failure when the current delay is zero.
+	Step time.Duration
+	// Multiplier is applied to the delay on failure.
+	Multiplier float64
+
+	mu      sync.Mutex
+	current time.Duration
+}
+
+// Default values for AdaptiveBackOff.
+const (
+	DefaultAdaptiveStep       = 100 * time.Millisecond
+	DefaultAdaptiveMultiplier = 2
+)
+
+// NewAdaptiveBackOff creates an AdaptiveBackOff with delays between min and max
+// and default Step and Multiplier.
+func NewAdaptiveBackOff(min, max time.Duration) *AdaptiveBackOff {
+	return &AdaptiveBackOff{
+		MinInterval: min,
+		MaxInterval: max,
+		Step:        DefaultAdaptiveStep,
+		Multiplier:  DefaultAdaptiveMultiplier,
+		current:     min,
+	}
+}
+
+// Reset does nothing. The delay is shared state learned from feedback and
+// must survive the Reset() calls made by the Retry functions.
+func (b *AdaptiveBackOff) Reset() {}
+
+// NextBackOff returns the current delay and records a failure.
+func (b *AdaptiveBackOff) NextBackOff() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	next := b.clamp(b.current)
+	b.increase()
+	return next
+}
+
+// Interval returns the current delay without changing it.
+func (b *AdaptiveBackOff) Interval() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.clamp(b.current)
+}
+
+// Success decreases the delay additively by Step.
+func (b *AdaptiveBackOff) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current = b.clamp(b.current - b.Step)
+}
+
+// Failure increases the delay multiplicatively by Multiplier.
+func (b *AdaptiveBackOff) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.increase()
+}
+
+func (b *AdaptiveBackOff) increase() {
+	if b.current <= 0 {
+		b.current = b.clamp(b.Step)
+		return
+	}
+	// Check for overflow, if overflow is detected set the delay to the max interval.
+	if float64(b.current) >= float64(b.MaxInterval)/b.Multiplier {
+		b.current = b.MaxInterval
+	} else {
+		b.current = b.clamp(time.Duration(float64(b.current) * b.Multiplier))
+	}
+}
+
+func (b *AdaptiveBackOff) clamp(d time.Duration) time.Duration {
+	if d < b.MinInterval {
+		return b.MinInterval
+	}
+	if d > b.MaxInterval {
+		return b.MaxInterval
+	}
+	return d
+}
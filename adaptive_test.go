@@ -0,0 +1,66 @@
+package backoff
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveBackOff(t *testing.T) {
+	b := NewAdaptiveBackOff(time.Second, 10*time.Second)
+	b.Step = 500 * time.Millisecond
+
+	// Failures increase the delay multiplicatively up to the max interval.
+	for _, expected := range []time.Duration{1, 2, 4, 8, 10, 10} {
+		assertEquals(t, expected*time.Second, b.NextBackOff())
+	}
+
+	// Successes decrease the delay additively down to the min interval.
+	for _, expected := range []time.Duration{9500, 9000, 8500} {
+		b.Success()
+		assertEquals(t, expected*time.Millisecond, b.Interval())
+	}
+	for i := 0; i < 100; i++ {
+		b.Success()
+	}
+	assertEquals(t, time.Second, b.Interval())
+
+	// Reset keeps the learned delay.
+	b.Failure()
+	b.Reset()
+	assertEquals(t, 2*time.Second, b.Interval())
+}
+
+func TestAdaptiveBackOffZeroMin(t *testing.T) {
+	b := NewAdaptiveBackOff(0, time.Second)
+
+	assertEquals(t, 0, b.NextBackOff())
+	assertEquals(t, DefaultAdaptiveStep, b.NextBackOff())
+	assertEquals(t, 2*DefaultAdaptiveStep, b.NextBackOff())
+	assertEquals(t, 4*DefaultAdaptiveStep, b.Interval())
+	for i := 0; i < 4; i++ {
+		b.Success()
+	}
+	assertEquals(t, 0, b.Interval())
+}
+
+func TestAdaptiveBackOffConcurrent(t *testing.T) {
+	b := NewAdaptiveBackOff(time.Millisecond, time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.NextBackOff()
+				b.Success()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if d := b.Interval(); d < b.MinInterval || d > b.MaxInterval {
+		t.Errorf("interval out of bounds: %s", d)
+	}
+}
@@ -0,0 +1,102 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AsyncRetry is a handle to an operation retried in the background by RetryAsync.
+type AsyncRetry[T any] struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	notify Notify
+
+	mu          sync.Mutex
+	res         T
+	err         error
+	attempts    int
+	nextAttempt time.Time
+}
+
+// RetryAsync is like RetryNotifyWithTimerAndData but runs the retry loop in a
+// new goroutine and returns immediately. Use the returned handle to wait for
+// and collect the outcome.
+func RetryAsync[T any](operation OperationWithData[T], b BackOff, notify Notify, t Timer) *AsyncRetry[T] {
+	ctx, cancel := context.WithCancel(getContext(b))
+	a := &AsyncRetry[T]{
+		done:   make(chan struct{}),
+		cancel: cancel,
+		notify: notify,
+	}
+	go func() {
+		defer cancel()
+		res, err := doRetryNotify(operation.withAttempt(), WithContext(b, ctx), a.observer(), t)
+		a.mu.Lock()
+		a.res, a.err = res, err
+		a.nextAttempt = time.Time{}
+		a.mu.Unlock()
+		close(a.done)
+	}()
+	return a
+}
+
+// Done returns a channel that is closed when the retry loop finishes.
+func (a *AsyncRetry[T]) Done() <-chan struct{} {
+	return a.done
+}
+
+// Result blocks until the retry loop finishes and returns its outcome.
+func (a *AsyncRetry[T]) Result() (T, error) {
+	<-a.done
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.res, a.err
+}
+
+// Cancel stops the retry loop. It does not interrupt a running attempt.
+// If the operation has not succeeded yet, Result returns context.Canceled.
+func (a *AsyncRetry[T]) Cancel() {
+	a.cancel()
+}
+
+// Attempts returns the number of attempts started so far.
+func (a *AsyncRetry[T]) Attempts() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.attempts
+}
+
+// NextAttempt returns the time the next attempt is scheduled for.
+// It returns the zero time if an attempt is running or the retry loop is finished.
+func (a *AsyncRetry[T]) NextAttempt() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.nextAttempt
+}
+
+func (a *AsyncRetry[T]) observer() Observer {
+	return asyncObserver[T]{AsyncRetry: a}
+}
+
+// asyncObserver records the progress of an AsyncRetry.
+type asyncObserver[T any] struct {
+	NopObserver
+	*AsyncRetry[T]
+}
+
+func (o asyncObserver[T]) OnAttemptStart(Attempt) {
+	o.mu.Lock()
+	o.attempts++
+	o.nextAttempt = time.Time{}
+	o.mu.Unlock()
+}
+
+func (o asyncObserver[T]) OnWait(_ Attempt, err error, next time.Duration) {
+	o.mu.Lock()
+	o.nextAttempt = time.Now().Add(next)
+	o.mu.Unlock()
+	if o.notify != nil {
+		o.notify(err, next)
+	}
+}
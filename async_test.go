@@ -0,0 +1,68 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryAsync(t *testing.T) {
+	const successOn = 3
+	var i = 0
+
+	f := func() (int, error) {
+		i++
+		if i == successOn {
+			return 42, nil
+		}
+		return 0, errors.New("error")
+	}
+
+	timer := newManualTimer()
+	a := RetryAsync(f, NewConstantBackOff(time.Minute), nil, timer)
+
+	for attempt := 1; attempt < successOn; attempt++ {
+		<-timer.started
+		if n := a.Attempts(); n != attempt {
+			t.Errorf("invalid number of attempts: %d", n)
+		}
+		if next := a.NextAttempt(); next.Before(time.Now().Add(59 * time.Second)) {
+			t.Errorf("invalid next attempt time: %s", next)
+		}
+		select {
+		case <-a.Done():
+			t.Fatal("retry is done before success")
+		default:
+		}
+		timer.fire()
+	}
+
+	res, err := a.Result()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if res != 42 {
+		t.Errorf("invalid data in response: %d, expected 42", res)
+	}
+	if n := a.Attempts(); n != successOn {
+		t.Errorf("invalid number of attempts: %d", n)
+	}
+	if next := a.NextAttempt(); !next.IsZero() {
+		t.Errorf("next attempt is scheduled after success: %s", next)
+	}
+}
+
+func TestRetryAsyncCancel(t *testing.T) {
+	f := func() (int, error) { return 0, errors.New("error") }
+
+	timer := newManualTimer()
+	a := RetryAsync(f, NewConstantBackOff(time.Hour), nil, timer)
+	<-timer.started
+	a.Cancel()
+
+	<-a.Done()
+	if _, err := a.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
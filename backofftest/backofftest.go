@@ -0,0 +1,163 @@
+// Package backofftest provides operations that fail in controlled ways for
+// testing retry configurations. Every invocation is recorded so tests can
+// assert on attempt counts and timing:
+//
+//	f := backofftest.FailN(2, errors.New("unavailable"))
+//	err := backoff.Retry(f.Operation(), backoff.NewConstantBackOff(time.Millisecond))
+//	// err == nil, f.Count() == 3
+package backofftest
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// ErrInjected is the error returned by FailUntil.
+var ErrInjected = errors.New("backofftest: injected failure")
+
+// Call is a recorded invocation of a Fault.
+type Call struct {
+	// Number is the 1-based number of the call.
+	Number int
+	// Time is the time the call started.
+	Time time.Time
+	// Err is the error returned by the call.
+	Err error
+}
+
+// Fault is an operation that fails according to a schedule.
+// It is safe for concurrent use.
+type Fault struct {
+	fail  func(n int, now time.Time) error
+	delay time.Duration
+	clock backoff.Clock
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+func newFault(fail func(n int, now time.Time) error) *Fault {
+	return &Fault{fail: fail, clock: backoff.SystemClock}
+}
+
+// FailN returns a Fault that fails with err on the first n calls and succeeds afterwards.
+func FailN(n int, err error) *Fault {
+	return newFault(func(i int, _ time.Time) error {
+		if i <= n {
+			return err
+		}
+		return nil
+	})
+}
+
+// FailWithProbability returns a Fault that fails with err with probability p
+// on each call. The outcomes are deterministic for a given seed.
+func FailWithProbability(p float64, err error, seed int64) *Fault {
+	r := rand.New(rand.NewSource(seed))
+	return newFault(func(int, time.Time) error {
+		if r.Float64() < p {
+			return err
+		}
+		return nil
+	})
+}
+
+// FailScript returns a Fault whose i-th call returns errs[i].
+// Calls after the end of the script succeed.
+func FailScript(errs []error) *Fault {
+	return newFault(func(i int, _ time.Time) error {
+		if i <= len(errs) {
+			return errs[i-1]
+		}
+		return nil
+	})
+}
+
+// Slow returns a Fault that succeeds after sleeping for d.
+func Slow(d time.Duration) *Fault {
+	return FailN(0, nil).Delay(d)
+}
+
+// FailUntil returns a Fault that fails with ErrInjected until t.
+func FailUntil(t time.Time) *Fault {
+	return newFault(func(_ int, now time.Time) error {
+		if now.Before(t) {
+			return ErrInjected
+		}
+		return nil
+	})
+}
+
+// Delay makes each call of f sleep for d before returning. It returns f.
+func (f *Fault) Delay(d time.Duration) *Fault {
+	f.delay = d
+	return f
+}
+
+// WithClock makes f read the time from clock. It returns f.
+func (f *Fault) WithClock(clock backoff.Clock) *Fault {
+	f.clock = clock
+	return f
+}
+
+func (f *Fault) call() error {
+	now := f.clock.Now()
+	f.mu.Lock()
+	n := len(f.calls) + 1
+	err := f.fail(n, now)
+	f.calls = append(f.calls, Call{Number: n, Time: now, Err: err})
+	f.mu.Unlock()
+
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	return err
+}
+
+// Operation returns f as a backoff.Operation.
+func (f *Fault) Operation() backoff.Operation {
+	return f.call
+}
+
+// OperationWithData returns f as a backoff.OperationWithData that returns value.
+func OperationWithData[T any](f *Fault, value T) backoff.OperationWithData[T] {
+	return func() (T, error) {
+		if err := f.call(); err != nil {
+			var zero T
+			return zero, err
+		}
+		return value, nil
+	}
+}
+
+// Calls returns the calls of f so far.
+func (f *Fault) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Count returns the number of calls of f so far.
+func (f *Fault) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+// Intervals returns the time between the starts of consecutive calls of f.
+func (f *Fault) Intervals() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.calls) < 2 {
+		return nil
+	}
+	intervals := make([]time.Duration, len(f.calls)-1)
+	for i := range intervals {
+		intervals[i] = f.calls[i+1].Time.Sub(f.calls[i].Time)
+	}
+	return intervals
+}
@@ -0,0 +1,108 @@
+package backofftest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+var errTest = errors.New("test")
+
+func TestFailN(t *testing.T) {
+	f := FailN(2, errTest)
+	err := backoff.Retry(f.Operation(), &backoff.ZeroBackOff{})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if f.Count() != 3 {
+		t.Errorf("invalid number of calls: %d", f.Count())
+	}
+	calls := f.Calls()
+	if calls[0].Err != errTest || calls[1].Err != errTest || calls[2].Err != nil || calls[2].Number != 3 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+}
+
+func TestFailScript(t *testing.T) {
+	errFatal := errors.New("fatal")
+	f := FailScript([]error{errTest, backoff.Permanent(errFatal), nil})
+	res, err := backoff.RetryWithData(OperationWithData(f, 42), &backoff.ZeroBackOff{})
+	if err != errFatal || res != 0 {
+		t.Errorf("unexpected result: %d, %v", res, err)
+	}
+	if f.Count() != 2 {
+		t.Errorf("invalid number of calls: %d", f.Count())
+	}
+}
+
+func TestFailWithProbability(t *testing.T) {
+	outcomes := func() []error {
+		f := FailWithProbability(0.5, errTest, 1)
+		for i := 0; i < 100; i++ {
+			_ = f.Operation()()
+		}
+		errs := make([]error, f.Count())
+		for i, c := range f.Calls() {
+			errs[i] = c.Err
+		}
+		return errs
+	}
+
+	first, second := outcomes(), outcomes()
+	var failures int
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatal("outcomes are not deterministic")
+		}
+		if first[i] != nil {
+			failures++
+		}
+	}
+	if failures < 25 || failures > 75 {
+		t.Errorf("unexpected number of failures: %d", failures)
+	}
+}
+
+// tickingClock advances by a second on each call to Now.
+type tickingClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *tickingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(time.Second)
+	return c.now
+}
+
+func TestFailUntil(t *testing.T) {
+	clock := &tickingClock{now: time.Unix(0, 0)}
+	f := FailUntil(time.Unix(3, 0)).WithClock(clock)
+	err := backoff.Retry(f.Operation(), &backoff.ZeroBackOff{})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if f.Count() != 3 {
+		t.Errorf("invalid number of calls: %d", f.Count())
+	}
+	for _, d := range f.Intervals() {
+		if d != time.Second {
+			t.Errorf("invalid interval: %s", d)
+		}
+	}
+}
+
+func TestSlow(t *testing.T) {
+	f := Slow(10 * time.Millisecond)
+	start := time.Now()
+	if err := f.Operation()(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Errorf("operation is not slow: %s", d)
+	}
+}
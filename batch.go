@@ -0,0 +1,78 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A BatchOperation is executing by RetryBatch().
+// It receives the items that are still pending and returns an error for each
+// item that failed. Items without an error in the returned map succeeded.
+// A non-nil error as the second return value fails the whole batch.
+//
+// If an item error is a *PermanentError, the item is not retried.
+// The operation must not modify pending.
+type BatchOperation[K comparable, V any] func(pending map[K]V) (map[K]error, error)
+
+// pendingError is returned to the retry loop while batch items are pending.
+type pendingError struct {
+	n int
+}
+
+func (e *pendingError) Error() string {
+	return fmt.Sprintf("backoff: %d batch items pending", e.n)
+}
+
+// RetryBatch calls o with items until every item succeeds, fails permanently
+// or BackOff stops. Only the items that have not succeeded yet are passed to
+// o on each attempt.
+//
+// It returns a result for each item: nil if the item succeeded, otherwise the
+// last error of the item.
+func RetryBatch[K comparable, V any](items map[K]V, o BatchOperation[K, V], b BackOff) map[K]error {
+	pending := make(map[K]V, len(items))
+	for k, v := range items {
+		pending[k] = v
+	}
+	results := make(map[K]error, len(items))
+	last := make(map[K]error)
+
+	_, err := doRetryNotify(func(Attempt) (struct{}, error) {
+		errs, err := o(pending)
+		if err != nil {
+			return struct{}{}, err
+		}
+		for k := range pending {
+			itemErr := errs[k]
+			if itemErr == nil {
+				results[k] = nil
+				delete(pending, k)
+				continue
+			}
+			var permanent *PermanentError
+			if errors.As(itemErr, &permanent) {
+				results[k] = permanent.Err
+				delete(pending, k)
+				continue
+			}
+			last[k] = itemErr
+		}
+		if len(pending) > 0 {
+			return struct{}{}, &pendingError{n: len(pending)}
+		}
+		return struct{}{}, nil
+	}, b, nil, nil)
+
+	// Items that are still pending failed with their last error if the policy
+	// stopped, or with the error that stopped the whole batch otherwise.
+	var pendingErr *pendingError
+	isPending := errors.As(err, &pendingErr)
+	for k := range pending {
+		if isPending && last[k] != nil {
+			results[k] = last[k]
+		} else {
+			results[k] = err
+		}
+	}
+	return results
+}
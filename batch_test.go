@@ -0,0 +1,74 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRetryBatch(t *testing.T) {
+	items := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	errTransient := errors.New("transient")
+	errFatal := errors.New("fatal")
+	var calls []int
+
+	o := func(pending map[string]int) (map[string]error, error) {
+		calls = append(calls, len(pending))
+		errs := make(map[string]error)
+		for k := range pending {
+			switch {
+			case k == "b" && len(calls) < 3:
+				errs[k] = errTransient
+			case k == "c":
+				errs[k] = Permanent(errFatal)
+			case k == "d":
+				errs[k] = errTransient
+			}
+		}
+		return errs, nil
+	}
+
+	results := RetryBatch(items, o, WithMaxRetries(&ZeroBackOff{}, 3))
+
+	if len(results) != len(items) {
+		t.Fatalf("invalid number of results: %d", len(results))
+	}
+	for k, expected := range map[string]error{"a": nil, "b": nil, "c": errFatal, "d": errTransient} {
+		if results[k] != expected {
+			t.Errorf("item %s: got %v, expected %v", k, results[k], expected)
+		}
+	}
+
+	expectedCalls := []int{4, 2, 2, 1}
+	if len(calls) != len(expectedCalls) {
+		t.Fatalf("got calls %v, expected %v", calls, expectedCalls)
+	}
+	for i := range calls {
+		if calls[i] != expectedCalls[i] {
+			t.Errorf("got calls %v, expected %v", calls, expectedCalls)
+		}
+	}
+}
+
+func TestRetryBatchWholeBatchError(t *testing.T) {
+	errDown := errors.New("down")
+	var calls int
+
+	o := func(pending map[string]int) (map[string]error, error) {
+		calls++
+		if calls == 1 {
+			return nil, errDown
+		}
+		return nil, Permanent(errDown)
+	}
+
+	results := RetryBatch(map[string]int{"a": 1, "b": 2}, o, &ZeroBackOff{})
+
+	if calls != 2 {
+		t.Errorf("invalid number of calls: %d", calls)
+	}
+	for k, err := range results {
+		if err != errDown {
+			t.Errorf("item %s: got %v, expected %v", k, err, errDown)
+		}
+	}
+}
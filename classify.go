@@ -0,0 +1,120 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// Classifier decides whether errors are transient, that is worth retrying,
+// from a list of rules. An error is transient if any rule matches it.
+//
+// Classifier is safe for concurrent use.
+type Classifier struct {
+	mu    sync.RWMutex
+	rules []func(error) bool
+}
+
+// NewClassifier returns a Classifier without rules.
+func NewClassifier() *Classifier {
+	return &Classifier{}
+}
+
+// DefaultClassifier is the Classifier used by IsTransient. It matches timeouts
+// of net.Error, temporary DNS errors, io.ErrUnexpectedEOF and, except on
+// Plan 9, the ECONNRESET, ECONNREFUSED, ECONNABORTED, ETIMEDOUT and EAGAIN
+// system errors, or their WSAE equivalents on Windows. context.DeadlineExceeded and context.Canceled are not
+// transient, since retrying with the same context fails again.
+// Add rules to it to extend IsTransient.
+var DefaultClassifier = newDefaultClassifier()
+
+func newDefaultClassifier() *Classifier {
+	c := NewClassifier()
+	c.AddTarget(io.ErrUnexpectedEOF)
+	addErrnoRules(c)
+	c.AddFunc(func(err error) bool {
+		var dnsErr *net.DNSError
+		return errors.As(err, &dnsErr) && (dnsErr.IsTemporary || dnsErr.IsTimeout)
+	})
+	c.AddFunc(func(err error) bool {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return false
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	})
+	return c
+}
+
+// IsTransient reports whether err is transient according to DefaultClassifier.
+func IsTransient(err error) bool {
+	return DefaultClassifier.IsTransient(err)
+}
+
+// AddTarget adds a rule matching errors for which errors.Is(err, target) is true.
+func (c *Classifier) AddTarget(target error) {
+	c.AddFunc(func(err error) bool {
+		return errors.Is(err, target)
+	})
+}
+
+// AddFunc adds a rule matching errors for which f returns true.
+func (c *Classifier) AddFunc(f func(error) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = append(c.rules, f)
+}
+
+// AddType adds a rule to c matching errors for which errors.As finds an error of type E.
+func AddType[E error](c *Classifier) {
+	c.AddFunc(func(err error) bool {
+		var target E
+		return errors.As(err, &target)
+	})
+}
+
+// IsTransient reports whether err matches any rule of c.
+// A nil error and a *PermanentError are never transient.
+func (c *Classifier) IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, rule := range c.rules {
+		if rule(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryableOnly wraps o so that the errors for which retryable returns false are
+// returned as a *PermanentError and not retried by the Retry functions:
+//
+//	err := backoff.Retry(backoff.RetryableOnly(operation, backoff.IsTransient), b)
+func RetryableOnly(o Operation, retryable func(error) bool) Operation {
+	op := RetryableOnlyWithData(o.withEmptyData(), retryable)
+	return func() error {
+		_, err := op()
+		return err
+	}
+}
+
+// RetryableOnlyWithData is like RetryableOnly for an OperationWithData.
+func RetryableOnlyWithData[T any](o OperationWithData[T], retryable func(error) bool) OperationWithData[T] {
+	return func() (T, error) {
+		res, err := o()
+		if err != nil && !retryable(err) {
+			return res, Permanent(err)
+		}
+		return res, err
+	}
+}
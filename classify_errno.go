@@ -0,0 +1,14 @@
+//go:build !plan9 && !windows
+
+package backoff
+
+import "syscall"
+
+// addErrnoRules adds the system errors of DefaultClassifier to c.
+func addErrnoRules(c *Classifier) {
+	c.AddTarget(syscall.ECONNRESET)
+	c.AddTarget(syscall.ECONNREFUSED)
+	c.AddTarget(syscall.ECONNABORTED)
+	c.AddTarget(syscall.ETIMEDOUT)
+	c.AddTarget(syscall.EAGAIN)
+}
@@ -0,0 +1,26 @@
+//go:build !plan9 && !windows
+
+package backoff
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestIsTransientErrno(t *testing.T) {
+	for _, testCase := range []struct {
+		err       error
+		transient bool
+	}{
+		{&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{syscall.EAGAIN, true},
+		{syscall.ENOENT, false},
+	} {
+		if got := IsTransient(testCase.err); got != testCase.transient {
+			t.Errorf("IsTransient(%v) = %t, expected %t", testCase.err, got, testCase.transient)
+		}
+	}
+}
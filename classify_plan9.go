@@ -0,0 +1,4 @@
+package backoff
+
+// addErrnoRules adds no rules on Plan 9, which has no errno values.
+func addErrnoRules(c *Classifier) {}
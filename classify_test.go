@@ -0,0 +1,78 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+)
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string { return fmt.Sprintf("status %d", e.code) }
+
+func TestIsTransient(t *testing.T) {
+	for _, testCase := range []struct {
+		err       error
+		transient bool
+	}{
+		{nil, false},
+		{errors.New("error"), false},
+		{io.EOF, false},
+		{io.ErrUnexpectedEOF, true},
+		{fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{&net.DNSError{Err: "server misbehaving", IsTemporary: true}, true},
+		{&net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{context.DeadlineExceeded, false},
+		{fmt.Errorf("dial: %w", context.DeadlineExceeded), false},
+		{context.Canceled, false},
+		{os.ErrDeadlineExceeded, true},
+		{Permanent(io.ErrUnexpectedEOF), false},
+	} {
+		if got := IsTransient(testCase.err); got != testCase.transient {
+			t.Errorf("IsTransient(%v) = %t, expected %t", testCase.err, got, testCase.transient)
+		}
+	}
+}
+
+func TestClassifierRules(t *testing.T) {
+	errBusy := errors.New("busy")
+
+	c := NewClassifier()
+	c.AddTarget(errBusy)
+	AddType[*statusError](c)
+
+	if !c.IsTransient(fmt.Errorf("wrapped: %w", errBusy)) {
+		t.Error("target is not transient")
+	}
+	if !c.IsTransient(fmt.Errorf("wrapped: %w", &statusError{503})) {
+		t.Error("type is not transient")
+	}
+	if c.IsTransient(io.ErrUnexpectedEOF) {
+		t.Error("empty classifier has default rules")
+	}
+}
+
+func TestRetryableOnly(t *testing.T) {
+	var calls int
+	o := func() error {
+		calls++
+		if calls < 3 {
+			return io.ErrUnexpectedEOF
+		}
+		return io.EOF
+	}
+
+	err := Retry(RetryableOnly(o, IsTransient), &ZeroBackOff{})
+	if err != io.EOF {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("invalid number of calls: %d", calls)
+	}
+}
@@ -0,0 +1,22 @@
+package backoff
+
+import "syscall"
+
+// Winsock errors returned by the net package on Windows. The syscall
+// ECONNRESET etc. constants do not match them there.
+const (
+	wsaEWOULDBLOCK  syscall.Errno = 10035
+	wsaECONNABORTED syscall.Errno = 10053
+	wsaECONNRESET   syscall.Errno = 10054
+	wsaETIMEDOUT    syscall.Errno = 10060
+	wsaECONNREFUSED syscall.Errno = 10061
+)
+
+// addErrnoRules adds the system errors of DefaultClassifier to c.
+func addErrnoRules(c *Classifier) {
+	c.AddTarget(wsaECONNRESET)
+	c.AddTarget(wsaECONNREFUSED)
+	c.AddTarget(wsaECONNABORTED)
+	c.AddTarget(wsaETIMEDOUT)
+	c.AddTarget(wsaEWOULDBLOCK)
+}
@@ -0,0 +1,24 @@
+package backoff
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestIsTransientErrno(t *testing.T) {
+	for _, testCase := range []struct {
+		err       error
+		transient bool
+	}{
+		{&net.OpError{Op: "dial", Err: os.NewSyscallError("connectex", syscall.Errno(10061))}, true},
+		{&net.OpError{Op: "read", Err: os.NewSyscallError("wsarecv", syscall.Errno(10054))}, true},
+		{syscall.Errno(10035), true},
+		{syscall.ERROR_FILE_NOT_FOUND, false},
+	} {
+		if got := IsTransient(testCase.err); got != testCase.transient {
+			t.Errorf("IsTransient(%v) = %t, expected %t", testCase.err, got, testCase.transient)
+		}
+	}
+}
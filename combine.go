@@ -0,0 +1,98 @@
+package backoff
+
+import "time"
+
+// StopMode tells when a CombinedBackOff stops.
+type StopMode int
+
+const (
+	// StopOnAny stops when any of the policies stops.
+	StopOnAny StopMode = iota
+	// StopOnAll stops when all of the policies stop. Policies that stopped
+	// are ignored until Reset() is called.
+	StopOnAll
+)
+
+// CombinedBackOff is a backoff policy that combines the delays of several
+// policies, see MaxOf, MinOf and SumOf. Reset() resets all of the policies.
+// A CombinedBackOff created as a struct literal uses the longest delay, like MaxOf.
+//
+// Note: Implementation is not thread-safe.
+type CombinedBackOff struct {
+	BackOffs []BackOff
+	StopMode StopMode
+
+	combine func(a, b time.Duration) time.Duration
+	stopped []bool
+}
+
+// MaxOf returns a policy whose delay is the longest of the delays of bs,
+// for example to wait for whichever is longer of a server-advised delay
+// and an exponential policy.
+func MaxOf(bs ...BackOff) *CombinedBackOff {
+	return &CombinedBackOff{BackOffs: bs, combine: maxDuration}
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+// MinOf returns a policy whose delay is the shortest of the delays of bs.
+func MinOf(bs ...BackOff) *CombinedBackOff {
+	return &CombinedBackOff{BackOffs: bs, combine: func(a, b time.Duration) time.Duration {
+		if b < a {
+			return b
+		}
+		return a
+	}}
+}
+
+// SumOf returns a policy whose delay is the sum of the delays of bs,
+// for example an exponential policy plus a constant floor.
+func SumOf(bs ...BackOff) *CombinedBackOff {
+	return &CombinedBackOff{BackOffs: bs, combine: func(a, b time.Duration) time.Duration {
+		return a + b
+	}}
+}
+
+func (b *CombinedBackOff) NextBackOff() time.Duration {
+	if len(b.stopped) != len(b.BackOffs) {
+		b.stopped = make([]bool, len(b.BackOffs))
+	}
+
+	combine := b.combine
+	if combine == nil {
+		combine = maxDuration
+	}
+
+	next := Stop
+	for i, bo := range b.BackOffs {
+		if b.stopped[i] {
+			continue
+		}
+		d := bo.NextBackOff()
+		if d == Stop {
+			if b.StopMode == StopOnAny {
+				return Stop
+			}
+			b.stopped[i] = true
+			continue
+		}
+		if next == Stop {
+			next = d
+		} else {
+			next = combine(next, d)
+		}
+	}
+	return next
+}
+
+func (b *CombinedBackOff) Reset() {
+	b.stopped = nil
+	for _, bo := range b.BackOffs {
+		bo.Reset()
+	}
+}
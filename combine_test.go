@@ -0,0 +1,65 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCombined(t *testing.T) {
+	newExp := func() BackOff {
+		return NewExponentialBackOff(
+			WithInitialInterval(time.Second),
+			WithRandomizationFactor(0),
+			WithMultiplier(2),
+			WithMaxElapsedTime(0),
+		)
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		b        *CombinedBackOff
+		expected []time.Duration
+	}{
+		{"max", MaxOf(newExp(), NewConstantBackOff(3*time.Second)), []time.Duration{3, 3, 4, 8}},
+		{"min", MinOf(newExp(), NewConstantBackOff(3*time.Second)), []time.Duration{1, 2, 3, 3}},
+		{"sum", SumOf(newExp(), NewConstantBackOff(3*time.Second)), []time.Duration{4, 5, 7, 11}},
+		{"literal", &CombinedBackOff{BackOffs: []BackOff{newExp(), NewConstantBackOff(3 * time.Second)}}, []time.Duration{3, 3, 4, 8}},
+	} {
+		for i, expected := range testCase.expected {
+			if d := testCase.b.NextBackOff(); d != expected*time.Second {
+				t.Errorf("%s: call %d: got %s, expected %s", testCase.name, i, d, expected*time.Second)
+			}
+		}
+		testCase.b.Reset()
+		if d := testCase.b.NextBackOff(); d != testCase.expected[0]*time.Second {
+			t.Errorf("%s: got %s after reset, expected %s", testCase.name, d, testCase.expected[0]*time.Second)
+		}
+	}
+}
+
+func TestCombinedStopMode(t *testing.T) {
+	b := MaxOf(WithMaxRetries(NewConstantBackOff(time.Minute), 1), NewConstantBackOff(time.Second))
+	assertEquals(t, time.Minute, b.NextBackOff())
+	assertEquals(t, Stop, b.NextBackOff())
+
+	b.Reset()
+	b.StopMode = StopOnAll
+	assertEquals(t, time.Minute, b.NextBackOff())
+	assertEquals(t, time.Second, b.NextBackOff())
+	assertEquals(t, time.Second, b.NextBackOff())
+
+	b = SumOf(&StopBackOff{}, &StopBackOff{})
+	b.StopMode = StopOnAll
+	assertEquals(t, Stop, b.NextBackOff())
+}
+
+func TestCombinedContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := MaxOf(&ZeroBackOff{}, WithContext(&ZeroBackOff{}, ctx))
+	if getContext(b) != ctx {
+		t.Error("invalid context")
+	}
+}
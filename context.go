@@ -45,6 +45,25 @@ func getContext(b BackOff) context.Context {
 	if tb, ok := b.(*backOffTries); ok {
 		return getContext(tb.delegate)
 	}
+	if qb, ok := b.(*backOffQuietReset); ok {
+		return getContext(qb.delegate)
+	}
+	if tb, ok := b.(*backOffTransform); ok {
+		return getContext(tb.delegate)
+	}
+	if mb, ok := b.(*backOffMaxElapsed); ok {
+		return getContext(mb.delegate)
+	}
+	if rb, ok := b.(*Recorder); ok {
+		return getContext(rb.delegate)
+	}
+	if cb, ok := b.(*CombinedBackOff); ok {
+		for _, bo := range cb.BackOffs {
+			if ctx := getContext(bo); ctx != context.Background() {
+				return ctx
+			}
+		}
+	}
 	return context.Background()
 }
 
@@ -0,0 +1,51 @@
+package backoff
+
+import "time"
+
+/*
+WithQuietReset creates a wrapper around another BackOff, which resets it
+when the time since the end of the last delay returned by NextBackOff()
+exceeds quiet, as measured by clock. SystemClock is used when nil is passed.
+Waiting for a delay is not quiet time, so delays longer than quiet do not
+cause a reset.
+
+Use it for long-lived policies that see rare failures spread out over time,
+so that a failure after a quiet period starts from the initial interval
+instead of the interval grown by earlier failures.
+
+Note: Implementation is not thread-safe.
+*/
+func WithQuietReset(b BackOff, quiet time.Duration, clock Clock) BackOff {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &backOffQuietReset{delegate: b, quiet: quiet, clock: clock}
+}
+
+type backOffQuietReset struct {
+	delegate  BackOff
+	quiet     time.Duration
+	clock     Clock
+	last      time.Time
+	lastDelay time.Duration
+}
+
+func (b *backOffQuietReset) NextBackOff() time.Duration {
+	now := b.clock.Now()
+	if !b.last.IsZero() && now.Sub(b.last.Add(b.lastDelay)) > b.quiet {
+		b.delegate.Reset()
+	}
+	next := b.delegate.NextBackOff()
+	b.last = now
+	b.lastDelay = 0
+	if next != Stop {
+		b.lastDelay = next
+	}
+	return next
+}
+
+func (b *backOffQuietReset) Reset() {
+	b.last = time.Time{}
+	b.lastDelay = 0
+	b.delegate.Reset()
+}
@@ -0,0 +1,62 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuietReset(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	exp := NewExponentialBackOff(
+		WithInitialInterval(time.Second),
+		WithRandomizationFactor(0),
+		WithMultiplier(2),
+		WithMaxElapsedTime(0),
+		WithClockProvider(clock),
+	)
+	b := WithQuietReset(exp, time.Minute, clock)
+
+	assertEquals(t, time.Second, b.NextBackOff())
+	clock.Advance(time.Minute)
+	assertEquals(t, 2*time.Second, b.NextBackOff())
+	clock.Advance(30 * time.Second)
+	assertEquals(t, 4*time.Second, b.NextBackOff())
+
+	// A failure after a quiet period starts from the initial interval.
+	clock.Advance(time.Hour)
+	assertEquals(t, time.Second, b.NextBackOff())
+	assertEquals(t, 2*time.Second, b.NextBackOff())
+}
+
+func TestQuietResetLongDelays(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	exp := NewExponentialBackOff(
+		WithInitialInterval(time.Second),
+		WithRandomizationFactor(0),
+		WithMultiplier(2),
+		WithMaxInterval(time.Hour),
+		WithMaxElapsedTime(0),
+		WithClockProvider(clock),
+	)
+	b := WithQuietReset(exp, 5*time.Second, clock)
+
+	// Waiting for a delay longer than quiet is not a quiet period.
+	expected := time.Second
+	for i := 0; i < 8; i++ {
+		next := b.NextBackOff()
+		assertEquals(t, expected, next)
+		clock.Advance(next)
+		expected *= 2
+	}
+}
+
+func TestQuietResetContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := WithQuietReset(WithContext(&ZeroBackOff{}, ctx), time.Minute, nil)
+	if getContext(b) != ctx {
+		t.Error("invalid context")
+	}
+}
@@ -0,0 +1,123 @@
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// JitterStrategy randomizes a backoff delay.
+type JitterStrategy func(d time.Duration) time.Duration
+
+// FullJitter returns a random delay in [0, d].
+func FullJitter(d time.Duration) time.Duration {
+	return time.Duration(rand.Float64() * float64(d))
+}
+
+// EqualJitter returns a random delay in [d/2, d].
+func EqualJitter(d time.Duration) time.Duration {
+	return d/2 + FullJitter(d-d/2)
+}
+
+// ProportionalJitter returns a JitterStrategy that randomizes the delay by
+// factor in both directions, the way ExponentialBackOff uses RandomizationFactor.
+func ProportionalJitter(factor float64) JitterStrategy {
+	return func(d time.Duration) time.Duration {
+		return getRandomValueFromInterval(factor, rand.Float64(), d)
+	}
+}
+
+/*
+WithJitter creates a wrapper around another BackOff, which randomizes
+the delays returned by NextBackOff() with strategy.
+
+Note: Implementation is not thread-safe.
+*/
+func WithJitter(b BackOff, strategy JitterStrategy) BackOff {
+	return &backOffTransform{delegate: b, transform: strategy}
+}
+
+/*
+WithMinMax creates a wrapper around another BackOff, which keeps the delays
+returned by NextBackOff() between min and max.
+
+Note: Implementation is not thread-safe.
+*/
+func WithMinMax(b BackOff, min, max time.Duration) BackOff {
+	return &backOffTransform{delegate: b, transform: func(d time.Duration) time.Duration {
+		if d < min {
+			return min
+		}
+		if d > max {
+			return max
+		}
+		return d
+	}}
+}
+
+/*
+WithScale creates a wrapper around another BackOff, which multiplies
+the delays returned by NextBackOff() by factor.
+
+Note: Implementation is not thread-safe.
+*/
+func WithScale(b BackOff, factor float64) BackOff {
+	return &backOffTransform{delegate: b, transform: func(d time.Duration) time.Duration {
+		return time.Duration(float64(d) * factor)
+	}}
+}
+
+// backOffTransform changes the delays of another BackOff.
+type backOffTransform struct {
+	delegate  BackOff
+	transform func(time.Duration) time.Duration
+}
+
+func (b *backOffTransform) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next == Stop {
+		return Stop
+	}
+	return b.transform(next)
+}
+
+func (b *backOffTransform) Reset() {
+	b.delegate.Reset()
+}
+
+/*
+WithMaxElapsed creates a wrapper around another BackOff, which returns Stop
+if waiting for the next delay would go past max since the last time Reset()
+was called, as measured by clock. SystemClock is used when nil is passed.
+
+It is the counterpart of ExponentialBackOff.MaxElapsedTime for any policy.
+
+Note: Implementation is not thread-safe.
+*/
+func WithMaxElapsed(b BackOff, max time.Duration, clock Clock) BackOff {
+	if clock == nil {
+		clock = SystemClock
+	}
+	mb := &backOffMaxElapsed{delegate: b, max: max, clock: clock}
+	mb.startTime = clock.Now()
+	return mb
+}
+
+type backOffMaxElapsed struct {
+	delegate  BackOff
+	max       time.Duration
+	clock     Clock
+	startTime time.Time
+}
+
+func (b *backOffMaxElapsed) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next == Stop || b.clock.Now().Sub(b.startTime)+next > b.max {
+		return Stop
+	}
+	return next
+}
+
+func (b *backOffMaxElapsed) Reset() {
+	b.startTime = b.clock.Now()
+	b.delegate.Reset()
+}
@@ -0,0 +1,75 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		strategy JitterStrategy
+		min, max time.Duration
+	}{
+		{"full", FullJitter, 0, time.Second},
+		{"equal", EqualJitter, 500 * time.Millisecond, time.Second},
+		{"proportional", ProportionalJitter(0.5), 500 * time.Millisecond, 1500 * time.Millisecond},
+	} {
+		b := WithJitter(NewConstantBackOff(time.Second), testCase.strategy)
+		for i := 0; i < 100; i++ {
+			if d := b.NextBackOff(); d < testCase.min || d > testCase.max {
+				t.Errorf("%s: delay out of range: %s", testCase.name, d)
+			}
+		}
+	}
+
+	b := WithJitter(&StopBackOff{}, FullJitter)
+	assertEquals(t, Stop, b.NextBackOff())
+}
+
+func TestMinMax(t *testing.T) {
+	exp := NewExponentialBackOff(
+		WithInitialInterval(time.Second),
+		WithRandomizationFactor(0),
+		WithMultiplier(2),
+		WithMaxElapsedTime(0),
+	)
+	b := WithMinMax(WithScale(exp, 0.5), time.Second, 3*time.Second)
+
+	for _, expected := range []time.Duration{1000, 1000, 2000, 3000, 3000} {
+		assertEquals(t, expected*time.Millisecond, b.NextBackOff())
+	}
+	b.Reset()
+	assertEquals(t, time.Second, b.NextBackOff())
+}
+
+func TestMaxElapsed(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := WithMaxElapsed(NewConstantBackOff(time.Second), 10*time.Second, clock)
+
+	assertEquals(t, time.Second, b.NextBackOff())
+	clock.Advance(9 * time.Second)
+	assertEquals(t, time.Second, b.NextBackOff())
+	clock.Advance(time.Millisecond)
+	assertEquals(t, Stop, b.NextBackOff())
+
+	b.Reset()
+	assertEquals(t, time.Second, b.NextBackOff())
+}
+
+func TestDecoratorsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var b BackOff = WithContext(&ZeroBackOff{}, ctx)
+	b = WithJitter(b, FullJitter)
+	b = WithMinMax(b, 0, time.Second)
+	b = WithScale(b, 2)
+	b = WithMaxElapsed(b, time.Minute, nil)
+	b = WithMaxRetries(b, 3)
+
+	if getContext(b) != ctx {
+		t.Error("invalid context")
+	}
+}
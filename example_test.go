@@ -20,7 +20,7 @@ func ExampleRetry() {
 	// Operation is successful.
 }
 
-func ExampleRetryContext() { // nolint: govet
+func ExampleWithContext() {
 	// A context
 	ctx := context.Background()
 
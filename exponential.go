@@ -1,6 +1,10 @@
 package backoff
 
 import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
 	"math/rand"
 	"time"
 )
@@ -214,3 +218,120 @@ func getRandomValueFromInterval(randomizationFactor, random float64, currentInte
 	// we want a 33% chance for selecting either 1, 2 or 3.
 	return time.Duration(minInterval + (random * (maxInterval - minInterval + 1)))
 }
+
+// exponentialState is the serialized form of ExponentialBackOff.
+type exponentialState struct {
+	InitialInterval     time.Duration `json:"initialInterval"`
+	RandomizationFactor float64       `json:"randomizationFactor"`
+	Multiplier          float64       `json:"multiplier"`
+	MaxInterval         time.Duration `json:"maxInterval"`
+	MaxElapsedTime      time.Duration `json:"maxElapsedTime"`
+	Stop                time.Duration `json:"stop"`
+	CurrentInterval     time.Duration `json:"currentInterval"`
+	Elapsed             time.Duration `json:"elapsed"`
+}
+
+const exponentialStateVersion = 1
+
+func (b *ExponentialBackOff) state() exponentialState {
+	return exponentialState{
+		InitialInterval:     b.InitialInterval,
+		RandomizationFactor: b.RandomizationFactor,
+		Multiplier:          b.Multiplier,
+		MaxInterval:         b.MaxInterval,
+		MaxElapsedTime:      b.MaxElapsedTime,
+		Stop:                b.Stop,
+		CurrentInterval:     b.currentInterval,
+		Elapsed:             b.GetElapsedTime(),
+	}
+}
+
+func (b *ExponentialBackOff) setState(s exponentialState) {
+	b.InitialInterval = s.InitialInterval
+	b.RandomizationFactor = s.RandomizationFactor
+	b.Multiplier = s.Multiplier
+	b.MaxInterval = s.MaxInterval
+	b.MaxElapsedTime = s.MaxElapsedTime
+	b.Stop = s.Stop
+	if b.Clock == nil {
+		b.Clock = SystemClock
+	}
+	b.currentInterval = s.CurrentInterval
+	b.startTime = b.Clock.Now().Add(-s.Elapsed)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It captures the
+// configuration and the progress of b, so a retry can be resumed with
+// UnmarshalBinary after a process restart. The Clock is not captured.
+func (b *ExponentialBackOff) MarshalBinary() ([]byte, error) {
+	s := b.state()
+	data := make([]byte, 1+8*8)
+	data[0] = exponentialStateVersion
+	for i, v := range []uint64{
+		uint64(s.InitialInterval),
+		math.Float64bits(s.RandomizationFactor),
+		math.Float64bits(s.Multiplier),
+		uint64(s.MaxInterval),
+		uint64(s.MaxElapsedTime),
+		uint64(s.Stop),
+		uint64(s.CurrentInterval),
+		uint64(s.Elapsed),
+	} {
+		binary.BigEndian.PutUint64(data[1+8*i:], v)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The elapsed time
+// is restored relative to the current time of b.Clock, so time spent while
+// the process was not running is not counted.
+func (b *ExponentialBackOff) UnmarshalBinary(data []byte) error {
+	if len(data) != 1+8*8 || data[0] != exponentialStateVersion {
+		return errors.New("backoff: invalid ExponentialBackOff encoding")
+	}
+	var v [8]uint64
+	for i := range v {
+		v[i] = binary.BigEndian.Uint64(data[1+8*i:])
+	}
+	b.setState(exponentialState{
+		InitialInterval:     time.Duration(v[0]),
+		RandomizationFactor: math.Float64frombits(v[1]),
+		Multiplier:          math.Float64frombits(v[2]),
+		MaxInterval:         time.Duration(v[3]),
+		MaxElapsedTime:      time.Duration(v[4]),
+		Stop:                time.Duration(v[5]),
+		CurrentInterval:     time.Duration(v[6]),
+		Elapsed:             time.Duration(v[7]),
+	})
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. See MarshalBinary.
+func (b *ExponentialBackOff) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.state())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See UnmarshalBinary.
+// Fields missing from data keep their current values, so a partial
+// configuration can be decoded into a policy created by NewExponentialBackOff.
+func (b *ExponentialBackOff) UnmarshalJSON(data []byte) error {
+	var s exponentialState
+	if b.Clock != nil {
+		s = b.state()
+	} else {
+		s = exponentialState{
+			InitialInterval:     b.InitialInterval,
+			RandomizationFactor: b.RandomizationFactor,
+			Multiplier:          b.Multiplier,
+			MaxInterval:         b.MaxInterval,
+			MaxElapsedTime:      b.MaxElapsedTime,
+			Stop:                b.Stop,
+			CurrentInterval:     b.currentInterval,
+		}
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b.setState(s)
+	return nil
+}
@@ -1,6 +1,7 @@
 package backoff
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 	"time"
@@ -159,3 +160,69 @@ func TestNewExponentialBackOff(t *testing.T) {
 		t.Errorf("Expected Clock to be SystemClock, got %v", backOff.Clock)
 	}
 }
+
+func TestExponentialBackOffMarshal(t *testing.T) {
+	for _, codec := range []struct {
+		name      string
+		marshal   func(*ExponentialBackOff) ([]byte, error)
+		unmarshal func(*ExponentialBackOff, []byte) error
+	}{
+		{"binary", (*ExponentialBackOff).MarshalBinary, (*ExponentialBackOff).UnmarshalBinary},
+		{"json", (*ExponentialBackOff).MarshalJSON, (*ExponentialBackOff).UnmarshalJSON},
+	} {
+		clock := &manualClock{now: time.Unix(0, 0)}
+		exp := NewExponentialBackOff(
+			WithRandomizationFactor(0),
+			WithMultiplier(2),
+			WithMaxElapsedTime(time.Hour),
+			WithClockProvider(clock),
+		)
+		exp.NextBackOff()
+		exp.NextBackOff()
+		clock.Advance(time.Minute)
+
+		data, err := codec.marshal(exp)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", codec.name, err)
+		}
+
+		// Restore in a process that started much later.
+		restoredClock := &manualClock{now: time.Unix(1000000, 0)}
+		restored := &ExponentialBackOff{Clock: restoredClock}
+		if err = codec.unmarshal(restored, data); err != nil {
+			t.Fatalf("%s: unexpected error: %s", codec.name, err)
+		}
+
+		if restored.Multiplier != 2 || restored.MaxElapsedTime != time.Hour || restored.Stop != Stop {
+			t.Errorf("%s: configuration is not restored: %+v", codec.name, restored)
+		}
+		assertEquals(t, time.Minute, restored.GetElapsedTime())
+		assertEquals(t, 2*time.Second, restored.NextBackOff())
+	}
+}
+
+func TestExponentialBackOffUnmarshalInvalid(t *testing.T) {
+	var exp ExponentialBackOff
+	if err := exp.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+		t.Error("error is unexpectedly nil")
+	}
+}
+
+func TestExponentialBackOffUnmarshalPartialJSON(t *testing.T) {
+	exp := NewExponentialBackOff(WithRandomizationFactor(0))
+	if err := json.Unmarshal([]byte(`{"InitialInterval":1000000000}`), exp); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if exp.InitialInterval != time.Second {
+		t.Errorf("InitialInterval is not decoded: %s", exp.InitialInterval)
+	}
+	if exp.Multiplier != DefaultMultiplier || exp.MaxInterval != DefaultMaxInterval ||
+		exp.MaxElapsedTime != DefaultMaxElapsedTime || exp.Stop != Stop {
+		t.Errorf("missing fields are not kept: %+v", exp)
+	}
+
+	exp.Reset()
+	assertEquals(t, time.Second, exp.NextBackOff())
+	assertEquals(t, 1500*time.Millisecond, exp.NextBackOff())
+}
@@ -0,0 +1,97 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+type retryOptions[T any] struct {
+	fallback func(err error) (T, error)
+}
+
+// RetryOption is a function type used to configure RetryWithData options.
+type RetryOption[T any] func(*retryOptions[T])
+
+// WithFallback sets a function that is called with the last error when the
+// retries give up, because the policy stopped, the operation returned a
+// *PermanentError or the context is done. Its result is returned instead,
+// so a degraded answer can be preferred over an error.
+func WithFallback[T any](fallback func(err error) (T, error)) RetryOption[T] {
+	return func(o *retryOptions[T]) {
+		o.fallback = fallback
+	}
+}
+
+func applyRetryOptions[T any](res T, err error, opts []RetryOption[T]) (T, error) {
+	var o retryOptions[T]
+	for _, fn := range opts {
+		fn(&o)
+	}
+	if err != nil && o.fallback != nil {
+		return o.fallback(err)
+	}
+	return res, err
+}
+
+// StaleCache keeps the last value successfully retrieved by Retry and returns
+// it when a later retry gives up, as long as it is not older than MaxStaleness.
+//
+// StaleCache is safe for concurrent use.
+type StaleCache[T any] struct {
+	// MaxStaleness is the maximum age of a value returned after a failure.
+	// Values never get too old if MaxStaleness == 0.
+	MaxStaleness time.Duration
+	Clock        Clock
+
+	mu    sync.Mutex
+	value T
+	at    time.Time
+	ok    bool
+}
+
+// NewStaleCache returns an empty StaleCache returning values up to maxStaleness old.
+func NewStaleCache[T any](maxStaleness time.Duration) *StaleCache[T] {
+	return &StaleCache[T]{MaxStaleness: maxStaleness, Clock: SystemClock}
+}
+
+// Retry retries o with b like RetryWithData and stores its result on success.
+// If the retries give up, it returns the stored value and its age with a nil
+// error, or the error if there is no stored value or it is too old.
+// age is zero for a value retrieved by this call.
+func (c *StaleCache[T]) Retry(o OperationWithData[T], b BackOff) (value T, age time.Duration, err error) {
+	var cached bool
+	value, err = RetryWithData(o, b, WithFallback(func(err error) (T, error) {
+		cached = true
+		var stale T
+		stale, age, err = c.stale(err)
+		return stale, err
+	}))
+	if err == nil && !cached {
+		c.mu.Lock()
+		c.value, c.at, c.ok = value, c.now(), true
+		c.mu.Unlock()
+	}
+	return value, age, err
+}
+
+// stale returns the stored value if it is fresh enough, err otherwise.
+func (c *StaleCache[T]) stale(err error) (T, time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var zero T
+	if !c.ok {
+		return zero, 0, err
+	}
+	age := c.now().Sub(c.at)
+	if c.MaxStaleness != 0 && age > c.MaxStaleness {
+		return zero, 0, err
+	}
+	return c.value, age, nil
+}
+
+func (c *StaleCache[T]) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
@@ -0,0 +1,73 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryWithDataFallback(t *testing.T) {
+	errDown := errors.New("down")
+	o := func() (string, error) { return "", errDown }
+
+	var fallbackErr error
+	res, err := RetryWithData(o, WithMaxRetries(&ZeroBackOff{}, 2), WithFallback(func(err error) (string, error) {
+		fallbackErr = err
+		return "degraded", nil
+	}))
+	if err != nil || res != "degraded" {
+		t.Errorf("unexpected result: %q, %v", res, err)
+	}
+	if fallbackErr != errDown {
+		t.Errorf("fallback is called with %v", fallbackErr)
+	}
+
+	// The fallback is not called on success.
+	res, err = RetryWithData(func() (string, error) { return "fresh", nil }, &ZeroBackOff{}, WithFallback(func(err error) (string, error) {
+		t.Error("fallback is called on success")
+		return "", err
+	}))
+	if err != nil || res != "fresh" {
+		t.Errorf("unexpected result: %q, %v", res, err)
+	}
+}
+
+func TestStaleCache(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	c := NewStaleCache[int](time.Minute)
+	c.Clock = clock
+
+	errDown := errors.New("down")
+	value := 0
+	o := func() (int, error) {
+		if value == 0 {
+			return 0, errDown
+		}
+		return value, nil
+	}
+	b := WithMaxRetries(&ZeroBackOff{}, 1)
+
+	// Nothing is cached yet.
+	if _, _, err := c.Retry(o, b); err != errDown {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	value = 42
+	res, age, err := c.Retry(o, b)
+	if err != nil || res != 42 || age != 0 {
+		t.Errorf("unexpected result: %d, %s, %v", res, age, err)
+	}
+
+	value = 0
+	clock.Advance(30 * time.Second)
+	res, age, err = c.Retry(o, b)
+	if err != nil || res != 42 || age != 30*time.Second {
+		t.Errorf("unexpected result: %d, %s, %v", res, age, err)
+	}
+
+	// Serving the stale value does not refresh it.
+	clock.Advance(31 * time.Second)
+	if _, _, err = c.Retry(o, b); err != errDown {
+		t.Errorf("too old value is returned: %v", err)
+	}
+}
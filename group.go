@@ -0,0 +1,88 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+)
+
+// Group deduplicates concurrent retries: callers of Do with the same key
+// share a single retried operation and all receive its result.
+//
+// The zero value is ready to use. Group is safe for concurrent use.
+type Group[K comparable, T any] struct {
+	mu    sync.Mutex
+	calls map[K]*groupCall[T]
+}
+
+type groupCall[T any] struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	res     T
+	err     error
+}
+
+// Do retries o with b like RetryWithData, unless a retry for key is already
+// in flight, in which case it waits for that retry and returns its result.
+// The policy b of the caller that started the retry is used.
+//
+// If ctx is done before the result is ready, Do returns the context error.
+// The shared retry continues as long as other callers wait for it; it is
+// canceled when all of them have left.
+func (g *Group[K, T]) Do(ctx context.Context, key K, o OperationWithData[T], b BackOff) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*groupCall[T])
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		c = g.start(key, o, b)
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.res, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// start runs the retry for key in a new goroutine. g.mu must be held.
+func (g *Group[K, T]) start(key K, o OperationWithData[T], b BackOff) *groupCall[T] {
+	ctx, cancel := context.WithCancel(getContext(b))
+	c := &groupCall[T]{done: make(chan struct{}), cancel: cancel}
+	g.calls[key] = c
+
+	go func() {
+		defer cancel()
+		c.res, c.err = RetryWithData(o, WithContext(b, ctx))
+
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	return c
+}
+
+// Forget makes the next call of Do for key start a new retry instead of
+// waiting for the one in flight.
+func (g *Group[K, T]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
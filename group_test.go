@@ -0,0 +1,111 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters waits until n callers wait for the retry of key in g.
+func waitForWaiters[K comparable, T any](g *Group[K, T], key K, n int) *groupCall[T] {
+	for {
+		g.mu.Lock()
+		c := g.calls[key]
+		ok := c != nil && c.waiters == n
+		g.mu.Unlock()
+		if ok {
+			return c
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var g Group[string, int]
+	var calls int32
+	release := make(chan struct{})
+
+	o := func() (int, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+			return 0, errors.New("error")
+		}
+		return 42, nil
+	}
+
+	const callers = 50
+	var wg sync.WaitGroup
+	results := make(chan int, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := g.Do(context.Background(), "key", o, &ZeroBackOff{})
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			results <- res
+		}()
+	}
+
+	waitForWaiters(&g, "key", callers)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for res := range results {
+		if res != 42 {
+			t.Errorf("invalid result: %d", res)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("invalid number of calls: %d", calls)
+	}
+}
+
+func TestGroupCancel(t *testing.T) {
+	var g Group[string, int]
+	o := func() (int, error) {
+		return 0, errors.New("error")
+	}
+	b := NewConstantBackOff(time.Hour)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	done1 := make(chan error)
+	done2 := make(chan error)
+	go func() {
+		_, err := g.Do(ctx1, "key", o, b)
+		done1 <- err
+	}()
+	waitForWaiters(&g, "key", 1)
+	go func() {
+		_, err := g.Do(ctx2, "key", o, b)
+		done2 <- err
+	}()
+	c := waitForWaiters(&g, "key", 2)
+
+	// One caller leaving does not abort the shared retry.
+	cancel1()
+	if err := <-done1; !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	select {
+	case <-c.done:
+		t.Fatal("shared retry is aborted while a caller waits")
+	default:
+	}
+
+	// The last caller leaving cancels it.
+	cancel2()
+	if err := <-done2; !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	<-c.done
+	if !errors.Is(c.err, context.Canceled) {
+		t.Errorf("shared retry is not canceled: %v", c.err)
+	}
+}
@@ -0,0 +1,126 @@
+// Package httpretry is an http.Handler middleware that sheds load and tells
+// rejected clients when to retry with a Retry-After header computed from a
+// backoff policy kept per client:
+//
+//	h := httpretry.NewRetryAfterHandler(mux, httpretry.ConcurrencyLimiter(100), func() backoff.BackOff {
+//		return backoff.NewExponentialBackOff(backoff.WithMaxElapsedTime(0))
+//	})
+package httpretry
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Limiter decides whether the server has capacity for a request.
+type Limiter interface {
+	// Acquire reports whether r may be served now. If it returns true,
+	// release must be called when r is done.
+	Acquire(r *http.Request) (release func(), ok bool)
+}
+
+// ConcurrencyLimiter returns a Limiter that allows at most n requests at a time.
+func ConcurrencyLimiter(n int) Limiter {
+	return concurrencyLimiter(make(chan struct{}, n))
+}
+
+type concurrencyLimiter chan struct{}
+
+func (l concurrencyLimiter) Acquire(*http.Request) (func(), bool) {
+	select {
+	case l <- struct{}{}:
+		return func() { <-l }, true
+	default:
+		return nil, false
+	}
+}
+
+// RemoteIPKey returns the IP address of the client of r.
+func RemoteIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// HeaderKey returns a function that keys requests by the value of the header name,
+// for example an API key.
+func HeaderKey(name string) func(*http.Request) string {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// RetryAfterHandler is an http.Handler middleware that sheds load. When the
+// Limiter has no capacity for a request, it responds with StatusCode and a
+// Retry-After header computed from a backoff policy kept per client, so that
+// clients that keep retrying during an overload are told to wait
+// progressively longer. Requests are served whenever the Limiter has
+// capacity, even if they come back before the advised time.
+//
+// The policy of a client is forgotten when one of its requests is served, or
+// after it has not been rejected for the TTL of Registry. When the policy of a client stops, it is replaced with a
+// new one, so a client is never rejected without a Retry-After header unless
+// its new policy stops at once.
+type RetryAfterHandler struct {
+	Next    http.Handler
+	Limiter Limiter
+	// Key identifies the client of a request.
+	Key func(*http.Request) string
+	// Registry keeps the policy of each client.
+	Registry *backoff.Registry[string]
+	// StatusCode is the status of rejected requests,
+	// usually http.StatusTooManyRequests or http.StatusServiceUnavailable.
+	StatusCode int
+}
+
+// DefaultRetryAfterTTL is the idle time after which NewRetryAfterHandler forgets a client.
+const DefaultRetryAfterTTL = 10 * time.Minute
+
+// NewRetryAfterHandler returns a RetryAfterHandler that serves requests with
+// next when limiter allows, keys clients by RemoteIPKey, creates their policies
+// with newBackOff and rejects requests with http.StatusTooManyRequests.
+func NewRetryAfterHandler(next http.Handler, limiter Limiter, newBackOff func() backoff.BackOff) *RetryAfterHandler {
+	return &RetryAfterHandler{
+		Next:       next,
+		Limiter:    limiter,
+		Key:        RemoteIPKey,
+		Registry:   backoff.NewRegistry[string](newBackOff, DefaultRetryAfterTTL),
+		StatusCode: http.StatusTooManyRequests,
+	}
+}
+
+func (h *RetryAfterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := h.Key(r)
+
+	if release, ok := h.Limiter.Acquire(r); ok {
+		defer release()
+		h.Registry.Success(key)
+		h.Next.ServeHTTP(w, r)
+		return
+	}
+
+	next := h.Registry.Failure(key)
+	if next == backoff.Stop {
+		// Start over instead of rejecting the client without Retry-After.
+		h.Registry.Success(key)
+		next = h.Registry.Failure(key)
+	}
+	if next == backoff.Stop {
+		// The new policy stopped at once too. Don't keep it.
+		h.Registry.Success(key)
+	} else {
+		seconds := int64(math.Ceil(next.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	http.Error(w, http.StatusText(h.StatusCode), h.StatusCode)
+}
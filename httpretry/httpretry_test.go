@@ -0,0 +1,161 @@
+package httpretry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// manualClock is a Clock that only moves when it is advanced.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestRetryAfterHandler(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	block := make(chan struct{})
+	started := make(chan struct{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			started <- struct{}{}
+			<-block
+		}
+	})
+
+	h := NewRetryAfterHandler(next, ConcurrencyLimiter(1), func() backoff.BackOff {
+		return backoff.NewExponentialBackOff(
+			backoff.WithInitialInterval(time.Second),
+			backoff.WithRandomizationFactor(0),
+			backoff.WithMultiplier(2),
+			backoff.WithMaxElapsedTime(0),
+			backoff.WithClockProvider(clock),
+		)
+	})
+	h.Key = HeaderKey("X-Client")
+	h.Registry.Clock = clock
+
+	serve := func(client, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("X-Client", client)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	assertResponse := func(w *httptest.ResponseRecorder, code int, retryAfter string) {
+		t.Helper()
+		if w.Code != code || w.Header().Get("Retry-After") != retryAfter {
+			t.Errorf("got %d with Retry-After %q, expected %d with %q", w.Code, w.Header().Get("Retry-After"), code, retryAfter)
+		}
+	}
+
+	// Occupy the only slot.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serve("a", "/slow")
+	}()
+	<-started
+
+	// Repeated offenders are told to wait longer.
+	assertResponse(serve("b", "/"), http.StatusTooManyRequests, "1")
+	clock.Advance(time.Second)
+	assertResponse(serve("b", "/"), http.StatusTooManyRequests, "2")
+	assertResponse(serve("b", "/"), http.StatusTooManyRequests, "4")
+	assertResponse(serve("c", "/"), http.StatusTooManyRequests, "1")
+
+	close(block)
+	<-done
+
+	// Clients are served as soon as there is capacity, and their policies are forgotten.
+	assertResponse(serve("b", "/"), http.StatusOK, "")
+	assertResponse(serve("c", "/"), http.StatusOK, "")
+	if n := h.Registry.Len(); n != 0 {
+		t.Errorf("%d policies kept after requests were served", n)
+	}
+}
+
+// switchLimiter admits requests when open is set.
+type switchLimiter struct {
+	open bool
+}
+
+func (l *switchLimiter) Acquire(*http.Request) (func(), bool) {
+	return func() {}, l.open
+}
+
+func TestRetryAfterHandlerRecovery(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	var served int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { served++ })
+	limiter := &switchLimiter{}
+	h := NewRetryAfterHandler(next, limiter, func() backoff.BackOff {
+		return backoff.NewExponentialBackOff(backoff.WithMaxElapsedTime(0), backoff.WithClockProvider(clock))
+	})
+	h.Registry.Clock = clock
+
+	serve := func() int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Code
+	}
+
+	if code := serve(); code != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status: %d", code)
+	}
+
+	// A client that retries more often than advised gets through once the server recovers.
+	limiter.open = true
+	for i := 0; i < 100; i++ {
+		clock.Advance(500 * time.Millisecond)
+		if code := serve(); code != http.StatusOK {
+			t.Fatalf("request %d: unexpected status: %d", i, code)
+		}
+	}
+	if served != 100 {
+		t.Errorf("invalid number of served requests: %d", served)
+	}
+}
+
+func TestRetryAfterHandlerStoppedPolicy(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := NewRetryAfterHandler(next, ConcurrencyLimiter(0), func() backoff.BackOff {
+		return backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 2)
+	})
+	h.Registry.Clock = clock
+
+	// A policy that stops is replaced, so every rejection has a Retry-After header.
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+			t.Errorf("request %d: got %d with Retry-After %q", i, w.Code, w.Header().Get("Retry-After"))
+		}
+		clock.Advance(time.Second)
+	}
+}
+
+func TestRemoteIPKey(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	if key := RemoteIPKey(r); key != "192.0.2.1" {
+		t.Errorf("unexpected key: %s", key)
+	}
+}
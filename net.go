@@ -0,0 +1,252 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// ReconnectingConn is a net.Conn that redials with a backoff policy when a
+// read or write fails. Data that was in flight on the failed connection is
+// lost, so it suits protocols where messages can be resent or are idempotent.
+//
+// Timeouts caused by deadlines are returned without reconnecting. Deadlines
+// are applied to each new connection.
+type ReconnectingConn struct {
+	dial        func(ctx context.Context) (net.Conn, error)
+	b           BackOff
+	onReconnect func(net.Conn)
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	mu            sync.Mutex
+	conn          net.Conn
+	closed        bool
+	redialing     *redialing
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+// redialing is a redial in progress. done is closed when it finishes.
+type redialing struct {
+	done chan struct{}
+	err  error
+}
+
+// DialReconnecting dials a connection with dial, retrying with b, and returns a
+// ReconnectingConn that redials with dial and b when the connection fails.
+// onReconnect is called with each new connection after the first, if it is not nil.
+// ctx limits the lifetime of all dials, not the lifetime of the connection.
+func DialReconnecting(ctx context.Context, dial func(ctx context.Context) (net.Conn, error), b BackOff, onReconnect func(net.Conn)) (*ReconnectingConn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	c := &ReconnectingConn{
+		dial:        dial,
+		b:           b,
+		onReconnect: onReconnect,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	conn, err := c.redial()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	c.conn = conn
+	return c, nil
+}
+
+func (c *ReconnectingConn) redial() (net.Conn, error) {
+	return RetryWithData(func() (net.Conn, error) {
+		return c.dial(c.ctx)
+	}, WithContext(c.b, c.ctx))
+}
+
+// current returns the current connection.
+func (c *ReconnectingConn) current() net.Conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn
+}
+
+// reconnect replaces the connection old with a new one, unless it was already
+// replaced by another goroutine. Only one goroutine redials at a time, others
+// wait for it and share its result. The lock is not held while redialing.
+func (c *ReconnectingConn) reconnect(old net.Conn) error {
+	c.mu.Lock()
+	for c.redialing != nil {
+		r := c.redialing
+		c.mu.Unlock()
+		<-r.done
+		if r.err != nil {
+			return r.err
+		}
+		c.mu.Lock()
+	}
+	if c.closed {
+		c.mu.Unlock()
+		return net.ErrClosed
+	}
+	if c.conn != old {
+		c.mu.Unlock()
+		return nil
+	}
+	r := &redialing{done: make(chan struct{})}
+	c.redialing = r
+	c.mu.Unlock()
+
+	old.Close()
+	conn, err := c.redial()
+
+	c.mu.Lock()
+	if err == nil && c.closed {
+		conn.Close()
+		err = net.ErrClosed
+	}
+	if err == nil {
+		if !c.readDeadline.IsZero() {
+			conn.SetReadDeadline(c.readDeadline)
+		}
+		if !c.writeDeadline.IsZero() {
+			conn.SetWriteDeadline(c.writeDeadline)
+		}
+		c.conn = conn
+	}
+	r.err = err
+	c.redialing = nil
+	close(r.done)
+	c.mu.Unlock()
+
+	if err == nil && c.onReconnect != nil {
+		c.onReconnect(conn)
+	}
+	return err
+}
+
+// shouldReconnect reports whether err means the connection is broken.
+func shouldReconnect(err error) bool {
+	var ne net.Error
+	return !(errors.As(err, &ne) && ne.Timeout())
+}
+
+// Read reads data from the connection, reconnecting until some data is read.
+func (c *ReconnectingConn) Read(p []byte) (int, error) {
+	for {
+		conn := c.current()
+		n, err := conn.Read(p)
+		if n > 0 || err == nil || !shouldReconnect(err) {
+			return n, err
+		}
+		if rerr := c.reconnect(conn); rerr != nil {
+			return n, err
+		}
+	}
+}
+
+// Write writes data to the connection. If the connection fails before any
+// byte of p is written, p is written to a new connection. If it fails after
+// a part of p is written, the number of bytes written and the error are
+// returned without reconnecting, since sending the rest of p on a new
+// connection would corrupt framed protocols; the next call reconnects.
+func (c *ReconnectingConn) Write(p []byte) (int, error) {
+	for {
+		conn := c.current()
+		n, err := conn.Write(p)
+		if n > 0 || err == nil || !shouldReconnect(err) {
+			return n, err
+		}
+		if rerr := c.reconnect(conn); rerr != nil {
+			return 0, err
+		}
+	}
+}
+
+// Close closes the connection and stops reconnecting.
+func (c *ReconnectingConn) Close() error {
+	c.cancel() // Interrupt a reconnect in progress.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return c.conn.Close()
+}
+
+func (c *ReconnectingConn) LocalAddr() net.Addr  { return c.current().LocalAddr() }
+func (c *ReconnectingConn) RemoteAddr() net.Addr { return c.current().RemoteAddr() }
+
+// SetDeadline sets the read and write deadlines of the current connection
+// and of the connections dialed after it.
+func (c *ReconnectingConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline, c.writeDeadline = t, t
+	return c.applyDeadline(c.conn.SetDeadline(t))
+}
+
+// SetReadDeadline sets the read deadline of the current connection and of
+// the connections dialed after it.
+func (c *ReconnectingConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return c.applyDeadline(c.conn.SetReadDeadline(t))
+}
+
+// SetWriteDeadline sets the write deadline of the current connection and of
+// the connections dialed after it.
+func (c *ReconnectingConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	return c.applyDeadline(c.conn.SetWriteDeadline(t))
+}
+
+// applyDeadline ignores the error of setting a deadline on a connection that
+// is being replaced, since the deadline is applied to the new one.
+func (c *ReconnectingConn) applyDeadline(err error) error {
+	if c.redialing != nil {
+		return nil
+	}
+	return err
+}
+
+// listener backs off on temporary Accept errors.
+type listener struct {
+	net.Listener
+
+	mu sync.Mutex
+	b  BackOff
+}
+
+// NewListener wraps l so that Accept sleeps for the delay returned by b and
+// tries again when l returns a temporary error, like net/http.Server does.
+// b is reset after each accepted connection. Accept returns the error if b stops.
+func NewListener(l net.Listener, b BackOff) net.Listener {
+	b.Reset()
+	return &listener{Listener: l, b: b}
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err == nil {
+			l.mu.Lock()
+			l.b.Reset()
+			l.mu.Unlock()
+			return conn, nil
+		}
+
+		var temporary interface{ Temporary() bool }
+		if !errors.As(err, &temporary) || !temporary.Temporary() {
+			return nil, err
+		}
+
+		l.mu.Lock()
+		next := l.b.NextBackOff()
+		l.mu.Unlock()
+		if next == Stop {
+			return nil, err
+		}
+		time.Sleep(next)
+	}
+}
@@ -0,0 +1,215 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestReconnectingConn(t *testing.T) {
+	servers := make(chan net.Conn, 2)
+	var dials int
+	dial := func(ctx context.Context) (net.Conn, error) {
+		dials++
+		if dials == 2 {
+			return nil, errors.New("refused")
+		}
+		client, server := net.Pipe()
+		servers <- server
+		return client, nil
+	}
+
+	var reconnects int
+	conn, err := DialReconnecting(context.Background(), dial, &ZeroBackOff{}, func(net.Conn) { reconnects++ })
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn.Close()
+
+	// The first server sends a message and drops the connection.
+	first := <-servers
+	go func() {
+		first.Write([]byte("one"))
+		first.Close()
+	}()
+	buf := make([]byte, 3)
+	if _, err = io.ReadFull(conn, buf); err != nil || string(buf) != "one" {
+		t.Fatalf("unexpected read: %q, %v", buf, err)
+	}
+
+	// The next read reconnects after a failed dial and reads from the second server.
+	go func() {
+		second := <-servers
+		second.Write([]byte("two"))
+	}()
+	if _, err = io.ReadFull(conn, buf); err != nil || string(buf) != "two" {
+		t.Fatalf("unexpected read: %q, %v", buf, err)
+	}
+	if dials != 3 || reconnects != 1 {
+		t.Errorf("invalid number of dials: %d, reconnects: %d", dials, reconnects)
+	}
+}
+
+func TestReconnectingConnWrite(t *testing.T) {
+	servers := make(chan net.Conn, 2)
+	dial := func(ctx context.Context) (net.Conn, error) {
+		client, server := net.Pipe()
+		servers <- server
+		return client, nil
+	}
+
+	conn, err := DialReconnecting(context.Background(), dial, &ZeroBackOff{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn.Close()
+
+	(<-servers).Close()
+	go func() {
+		second := <-servers
+		buf := make([]byte, 5)
+		io.ReadFull(second, buf)
+		second.Write(buf)
+	}()
+
+	if n, err := conn.Write([]byte("hello")); n != 5 || err != nil {
+		t.Fatalf("unexpected write: %d, %v", n, err)
+	}
+	buf := make([]byte, 5)
+	if _, err = io.ReadFull(conn, buf); err != nil || string(buf) != "hello" {
+		t.Fatalf("unexpected read: %q, %v", buf, err)
+	}
+}
+
+func TestReconnectingConnDeadline(t *testing.T) {
+	var dials int
+	dial := func(ctx context.Context) (net.Conn, error) {
+		dials++
+		client, _ := net.Pipe()
+		return client, nil
+	}
+
+	conn, err := DialReconnecting(context.Background(), dial, &ZeroBackOff{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	var ne net.Error
+	if _, err = conn.Read(make([]byte, 1)); !errors.As(err, &ne) || !ne.Timeout() {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if dials != 1 {
+		t.Errorf("reconnected on timeout: %d dials", dials)
+	}
+}
+
+// brokenConn writes n bytes of each write and fails, and records its read deadline.
+type brokenConn struct {
+	net.Conn
+	n            int
+	readDeadline time.Time
+}
+
+func (c *brokenConn) Write(p []byte) (int, error) {
+	return c.n, io.ErrClosedPipe
+}
+
+func (c *brokenConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline = t
+	return nil
+}
+
+func TestReconnectingConnPartialWrite(t *testing.T) {
+	var dials int
+	dial := func(ctx context.Context) (net.Conn, error) {
+		dials++
+		client, _ := net.Pipe()
+		return &brokenConn{Conn: client, n: 2}, nil
+	}
+
+	conn, err := DialReconnecting(context.Background(), dial, &ZeroBackOff{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn.Close()
+
+	// The rest of a partially written buffer is not sent on a new connection.
+	if n, err := conn.Write([]byte("hello")); n != 2 || err != io.ErrClosedPipe {
+		t.Errorf("unexpected write: %d, %v", n, err)
+	}
+	if dials != 1 {
+		t.Errorf("reconnected after partial write: %d dials", dials)
+	}
+}
+
+func TestReconnectingConnDeadlineAfterReconnect(t *testing.T) {
+	conns := make(chan *brokenConn, 2)
+	dial := func(ctx context.Context) (net.Conn, error) {
+		client, _ := net.Pipe()
+		c := &brokenConn{Conn: client}
+		conns <- c
+		return c, nil
+	}
+
+	conn, err := DialReconnecting(context.Background(), dial, &ZeroBackOff{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(time.Hour)
+	conn.SetReadDeadline(deadline)
+	first := <-conns
+	if err = conn.reconnect(first); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if second := <-conns; !second.readDeadline.Equal(deadline) {
+		t.Errorf("deadline not applied to new connection: %v", second.readDeadline)
+	}
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Timeout() bool   { return false }
+func (temporaryError) Temporary() bool { return true }
+
+type failingListener struct {
+	net.Listener
+	errs []error
+}
+
+func (l *failingListener) Accept() (net.Conn, error) {
+	if len(l.errs) > 0 {
+		err := l.errs[0]
+		l.errs = l.errs[1:]
+		return nil, err
+	}
+	client, _ := net.Pipe()
+	return client, nil
+}
+
+func TestListener(t *testing.T) {
+	l := NewListener(&failingListener{errs: []error{temporaryError{}, temporaryError{}}}, WithMaxRetries(&ZeroBackOff{}, 2))
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	conn.Close()
+
+	// The policy is reset after a connection is accepted.
+	l.(*listener).Listener.(*failingListener).errs = []error{temporaryError{}, temporaryError{}, temporaryError{}}
+	if _, err = l.Accept(); err != (temporaryError{}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	l.(*listener).Listener.(*failingListener).errs = []error{net.ErrClosed}
+	if _, err = l.Accept(); err != net.ErrClosed {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
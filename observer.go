@@ -0,0 +1,73 @@
+package backoff
+
+import "time"
+
+// StopReason tells why the retry loop gave up.
+type StopReason int
+
+const (
+	// ReasonBackOff means the backoff policy returned Stop.
+	ReasonBackOff StopReason = iota
+	// ReasonPermanent means the operation returned a *PermanentError.
+	ReasonPermanent
+	// ReasonContext means the context of the backoff policy is done.
+	ReasonContext
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case ReasonBackOff:
+		return "backoff stopped"
+	case ReasonPermanent:
+		return "permanent error"
+	case ReasonContext:
+		return "context done"
+	default:
+		return "unknown"
+	}
+}
+
+// Observer receives events for the full lifecycle of a retry loop.
+// Methods are called synchronously from the goroutine running the loop.
+type Observer interface {
+	// OnAttemptStart is called before the operation is called.
+	OnAttemptStart(a Attempt)
+	// OnAttemptFailure is called when the operation returns an error.
+	OnAttemptFailure(a Attempt, err error)
+	// OnWait is called before sleeping for the duration next
+	// returned by the backoff policy.
+	OnWait(a Attempt, err error, next time.Duration)
+	// OnSuccess is called when the operation succeeds with the total
+	// number of attempts and the time elapsed since the first one started.
+	OnSuccess(attempts int, elapsed time.Duration)
+	// OnGiveUp is called when the loop stops without success with the
+	// error it is about to return and the reason for stopping.
+	OnGiveUp(attempts int, elapsed time.Duration, err error, reason StopReason)
+}
+
+// NopObserver is an Observer that does nothing.
+// Embed it to implement only the methods you need.
+type NopObserver struct{}
+
+func (NopObserver) OnAttemptStart(Attempt)                         {}
+func (NopObserver) OnAttemptFailure(Attempt, error)                {}
+func (NopObserver) OnWait(Attempt, error, time.Duration)           {}
+func (NopObserver) OnSuccess(int, time.Duration)                   {}
+func (NopObserver) OnGiveUp(int, time.Duration, error, StopReason) {}
+
+// notifyObserver adapts a Notify function to the Observer interface.
+type notifyObserver struct {
+	NopObserver
+	notify Notify
+}
+
+func (o notifyObserver) OnWait(_ Attempt, err error, next time.Duration) {
+	o.notify(err, next)
+}
+
+func observerFromNotify(notify Notify) Observer {
+	if notify == nil {
+		return NopObserver{}
+	}
+	return notifyObserver{notify: notify}
+}
@@ -0,0 +1,120 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnAttemptStart(a Attempt) {
+	o.events = append(o.events, fmt.Sprintf("start %d", a.Number))
+}
+
+func (o *recordingObserver) OnAttemptFailure(a Attempt, err error) {
+	o.events = append(o.events, fmt.Sprintf("failure %d: %s", a.Number, err))
+}
+
+func (o *recordingObserver) OnWait(a Attempt, err error, next time.Duration) {
+	o.events = append(o.events, fmt.Sprintf("wait %d: %s", a.Number, next))
+}
+
+func (o *recordingObserver) OnSuccess(attempts int, elapsed time.Duration) {
+	o.events = append(o.events, fmt.Sprintf("success %d", attempts))
+}
+
+func (o *recordingObserver) OnGiveUp(attempts int, elapsed time.Duration, err error, reason StopReason) {
+	o.events = append(o.events, fmt.Sprintf("give up %d: %s (%s)", attempts, err, reason))
+}
+
+func assertEvents(t *testing.T, got, expected []string) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("got events %q, expected %q", got, expected)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("event %d: got %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestRetryObserveSuccess(t *testing.T) {
+	var i int
+	f := func(Attempt) (struct{}, error) {
+		i++
+		if i == 2 {
+			return struct{}{}, nil
+		}
+		return struct{}{}, errors.New("error")
+	}
+
+	obs := &recordingObserver{}
+	_, err := RetryObserveWithTimerAndAttempt(f, NewConstantBackOff(time.Second), obs, &testTimer{})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	assertEvents(t, obs.events, []string{
+		"start 1",
+		"failure 1: error",
+		"wait 1: 1s",
+		"start 2",
+		"success 2",
+	})
+}
+
+func TestRetryObserveGiveUp(t *testing.T) {
+	f := func() error { return errors.New("error") }
+
+	obs := &recordingObserver{}
+	err := RetryObserve(f, WithMaxRetries(&ZeroBackOff{}, 1), obs)
+	if err == nil {
+		t.Errorf("error is unexpectedly nil")
+	}
+	assertEvents(t, obs.events, []string{
+		"start 1",
+		"failure 1: error",
+		"wait 1: 0s",
+		"start 2",
+		"failure 2: error",
+		"give up 2: error (backoff stopped)",
+	})
+}
+
+func TestRetryObservePermanent(t *testing.T) {
+	f := func() (int, error) { return 0, Permanent(errors.New("fatal")) }
+
+	obs := &recordingObserver{}
+	_, err := RetryObserveWithData(f, &ZeroBackOff{}, obs)
+	if err == nil || err.Error() != "fatal" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertEvents(t, obs.events, []string{
+		"start 1",
+		"failure 1: fatal",
+		"give up 1: fatal (permanent error)",
+	})
+}
+
+func TestRetryObserveContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	f := func() error { return errors.New("error") }
+
+	obs := &recordingObserver{}
+	err := RetryObserve(f, WithContext(&ZeroBackOff{}, ctx), obs)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertEvents(t, obs.events, []string{
+		"start 1",
+		"failure 1: error",
+		"give up 1: context canceled (context done)",
+	})
+}
@@ -0,0 +1,84 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrConditionNotMet is returned by Poll and PollValue when the backoff
+// policy stops before the condition is met.
+var ErrConditionNotMet = errors.New("backoff: condition not met")
+
+// A Condition is polled by Poll(). It returns true when the awaited state
+// is reached and false if it is not reached yet.
+type Condition func(ctx context.Context) (done bool, err error)
+
+// A ValueCondition is polled by PollValue(). It is like a Condition but
+// returns a value too.
+type ValueCondition[T any] func(ctx context.Context) (value T, done bool, err error)
+
+type pollOptions struct {
+	transient func(error) bool
+}
+
+// PollOption is a function type used to configure Poll and PollValue.
+type PollOption func(*pollOptions)
+
+// PollTransientErrors makes errors returned by the condition transient:
+// polling continues as if the condition returned false.
+// By default errors are fatal and stop polling.
+func PollTransientErrors() PollOption {
+	return PollTransientErrorsIf(func(error) bool { return true })
+}
+
+// PollTransientErrorsIf makes errors for which transient returns true
+// transient, see PollTransientErrors. Other errors are fatal.
+func PollTransientErrorsIf(transient func(error) bool) PollOption {
+	return func(o *pollOptions) {
+		o.transient = transient
+	}
+}
+
+// Poll calls cond until it returns true, BackOff stops or ctx is done.
+// cond is guaranteed to be run at least once.
+//
+// Poll returns nil when cond returns true. It returns the error of cond if the
+// error is fatal, or if the error is transient and polling stops after it.
+// It returns ErrConditionNotMet if polling stops after cond returned false and
+// the context error if ctx is done.
+//
+// Errors wrapped in a *PermanentError are always fatal.
+func Poll(ctx context.Context, b BackOff, cond Condition, opts ...PollOption) error {
+	_, err := PollValue(ctx, b, func(ctx context.Context) (struct{}, bool, error) {
+		done, err := cond(ctx)
+		return struct{}{}, done, err
+	}, opts...)
+	return err
+}
+
+// PollValue is like Poll but returns the value of cond when it is met.
+func PollValue[T any](ctx context.Context, b BackOff, cond ValueCondition[T], opts ...PollOption) (T, error) {
+	var o pollOptions
+	for _, fn := range opts {
+		fn(&o)
+	}
+
+	res, err := doRetryNotify(func(Attempt) (T, error) {
+		res, done, err := cond(ctx)
+		if err != nil {
+			if o.transient == nil || !o.transient(err) {
+				return res, Permanent(err)
+			}
+			return res, err
+		}
+		if !done {
+			return res, ErrConditionNotMet
+		}
+		return res, nil
+	}, WithContext(b, ctx), nil, nil)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return res, nil
+}
@@ -0,0 +1,99 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	var i int
+	err := Poll(context.Background(), &ZeroBackOff{}, func(context.Context) (bool, error) {
+		i++
+		return i == 3, nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if i != 3 {
+		t.Errorf("invalid number of polls: %d", i)
+	}
+}
+
+func TestPollNotMet(t *testing.T) {
+	err := Poll(context.Background(), WithMaxRetries(&ZeroBackOff{}, 2), func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if err != ErrConditionNotMet {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPollErrors(t *testing.T) {
+	errTransient := errors.New("transient")
+	errFatal := errors.New("fatal")
+
+	for _, testCase := range []struct {
+		name          string
+		opts          []PollOption
+		err           error
+		expectedPolls int
+	}{
+		{"fatal by default", nil, errTransient, 1},
+		{"transient", []PollOption{PollTransientErrors()}, errTransient, 3},
+		{"permanent", []PollOption{PollTransientErrors()}, Permanent(errFatal), 1},
+		{
+			"classified",
+			[]PollOption{PollTransientErrorsIf(func(err error) bool { return err == errTransient })},
+			errFatal,
+			1,
+		},
+	} {
+		var polls int
+		res, err := PollValue(context.Background(), WithMaxRetries(&ZeroBackOff{}, 2), func(context.Context) (int, bool, error) {
+			polls++
+			return 42, false, testCase.err
+		}, testCase.opts...)
+
+		if err == nil || !errors.Is(testCase.err, err) {
+			t.Errorf("%s: unexpected error: %v", testCase.name, err)
+		}
+		if res != 0 {
+			t.Errorf("%s: unexpected value: %d", testCase.name, res)
+		}
+		if polls != testCase.expectedPolls {
+			t.Errorf("%s: invalid number of polls: %d", testCase.name, polls)
+		}
+	}
+}
+
+func TestPollValue(t *testing.T) {
+	var i int
+	res, err := PollValue(context.Background(), &ZeroBackOff{}, func(context.Context) (string, bool, error) {
+		i++
+		if i < 2 {
+			return "", false, errors.New("not ready")
+		}
+		return "ready", true, nil
+	}, PollTransientErrors())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if res != "ready" {
+		t.Errorf("unexpected value: %s", res)
+	}
+}
+
+func TestPollContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := Poll(ctx, NewConstantBackOff(time.Hour), func(context.Context) (bool, error) {
+		cancel()
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
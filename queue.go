@@ -0,0 +1,282 @@
+package backoff
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Queue is a work queue that delays failed items with a backoff policy per
+// item, so that thousands of items can be retried without a goroutine
+// sleeping for each of them.
+//
+// An item is processed by at most one worker at a time: adding an item that
+// is already queued has no effect, and adding an item that is being processed
+// queues it again once Done is called for it.
+//
+// Queue is safe for concurrent use.
+type Queue[T comparable] struct {
+	newBackOff func() BackOff
+	clock      Clock
+	timer      Timer
+
+	mu           sync.Mutex
+	cond         *sync.Cond
+	queue        []T
+	dirty        map[T]struct{}
+	processing   map[T]struct{}
+	waiting      waitingHeap[T]
+	waitingIndex map[T]*waitingItem[T]
+	backOffs     map[T]BackOff
+	shuttingDown bool
+
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewQueue returns a new Queue that creates the backoff policy of each item
+// with newBackOff. Delays are measured with clock and waited for with timer.
+// SystemClock and a default timer that uses system timer are used when nil is passed.
+func NewQueue[T comparable](newBackOff func() BackOff, clock Clock, timer Timer) *Queue[T] {
+	if clock == nil {
+		clock = SystemClock
+	}
+	if timer == nil {
+		timer = &defaultTimer{}
+	}
+	q := &Queue[T]{
+		newBackOff:   newBackOff,
+		clock:        clock,
+		timer:        timer,
+		dirty:        make(map[T]struct{}),
+		processing:   make(map[T]struct{}),
+		waitingIndex: make(map[T]*waitingItem[T]),
+		backOffs:     make(map[T]BackOff),
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// Add marks item as needing processing.
+func (q *Queue[T]) Add(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.add(item)
+}
+
+func (q *Queue[T]) add(item T) {
+	if q.shuttingDown {
+		return
+	}
+	if _, ok := q.dirty[item]; ok {
+		return
+	}
+	q.dirty[item] = struct{}{}
+	if _, ok := q.processing[item]; ok {
+		return
+	}
+	q.queue = append(q.queue, item)
+	q.cond.Signal()
+}
+
+// AddAfter adds item after the duration d has passed.
+// If item is already waiting, the earlier of the two times is kept.
+func (q *Queue[T]) AddAfter(item T, d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if d <= 0 {
+		q.add(item)
+		return
+	}
+
+	readyAt := q.clock.Now().Add(d)
+	if w, ok := q.waitingIndex[item]; ok {
+		if readyAt.Before(w.readyAt) {
+			w.readyAt = readyAt
+			heap.Fix(&q.waiting, w.index)
+		}
+	} else {
+		w = &waitingItem[T]{item: item, readyAt: readyAt}
+		heap.Push(&q.waiting, w)
+		q.waitingIndex[item] = w
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// AddRateLimited adds item after the delay returned by its backoff policy.
+// The policy of item is created on first use and kept until Forget is called.
+// If the policy stops, item is not added and false is returned.
+func (q *Queue[T]) AddRateLimited(item T) bool {
+	q.mu.Lock()
+	b, ok := q.backOffs[item]
+	if !ok {
+		b = q.newBackOff()
+		b.Reset()
+		q.backOffs[item] = b
+	}
+	next := b.NextBackOff()
+	q.mu.Unlock()
+
+	if next == Stop {
+		return false
+	}
+	q.AddAfter(item, next)
+	return true
+}
+
+// Forget discards the backoff policy of item, so the next call to
+// AddRateLimited starts from the initial delay. Call it when item is
+// processed successfully.
+func (q *Queue[T]) Forget(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.backOffs, item)
+}
+
+// Get blocks until an item is ready for processing and returns it.
+// Done must be called for the item once it is processed.
+// shutdown is true if the queue is shut down and has no more items.
+func (q *Queue[T]) Get() (item T, shutdown bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.queue) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.queue) == 0 {
+		return item, true
+	}
+
+	item = q.queue[0]
+	var zero T
+	q.queue[0] = zero // Let the item be garbage collected.
+	q.queue = q.queue[1:]
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+	return item, false
+}
+
+// Done marks item as processed. If item was added again while it was being
+// processed, it is queued again.
+func (q *Queue[T]) Done(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.processing, item)
+	if _, ok := q.dirty[item]; ok {
+		q.queue = append(q.queue, item)
+		q.cond.Signal()
+	}
+}
+
+// Len returns the number of items ready for processing.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queue)
+}
+
+// ShutDown stops the queue from accepting new items and drops items that are
+// waiting for their delay. Items that are already queued are still returned by
+// Get, after which Get reports shutdown.
+func (q *Queue[T]) ShutDown() {
+	q.mu.Lock()
+	if q.shuttingDown {
+		q.mu.Unlock()
+		<-q.stopped
+		return
+	}
+	q.shuttingDown = true
+	q.waiting = nil
+	q.waitingIndex = make(map[T]*waitingItem[T])
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	close(q.stop)
+	<-q.stopped
+}
+
+// ShuttingDown reports whether ShutDown has been called.
+func (q *Queue[T]) ShuttingDown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shuttingDown
+}
+
+// run moves waiting items to the queue when their delay has passed.
+func (q *Queue[T]) run() {
+	defer close(q.stopped)
+	defer q.timer.Stop()
+
+	// armedAt is the time the timer is started for, zero if it is not running.
+	var armedAt time.Time
+	for {
+		q.mu.Lock()
+		now := q.clock.Now()
+		for len(q.waiting) > 0 && !q.waiting[0].readyAt.After(now) {
+			w := heap.Pop(&q.waiting).(*waitingItem[T])
+			delete(q.waitingIndex, w.item)
+			q.add(w.item)
+		}
+		var afterC <-chan time.Time
+		if len(q.waiting) > 0 {
+			if readyAt := q.waiting[0].readyAt; !readyAt.Equal(armedAt) {
+				q.timer.Start(readyAt.Sub(now))
+				armedAt = readyAt
+			}
+			afterC = q.timer.C()
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-afterC:
+			armedAt = time.Time{}
+		case <-q.wake:
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+type waitingItem[T any] struct {
+	item    T
+	readyAt time.Time
+	index   int
+}
+
+// waitingHeap is a min-heap of items ordered by the time they are ready.
+type waitingHeap[T any] []*waitingItem[T]
+
+func (h waitingHeap[T]) Len() int           { return len(h) }
+func (h waitingHeap[T]) Less(i, j int) bool { return h[i].readyAt.Before(h[j].readyAt) }
+
+func (h waitingHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waitingHeap[T]) Push(x interface{}) {
+	w := x.(*waitingItem[T])
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waitingHeap[T]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return w
+}
@@ -0,0 +1,166 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+// manualTimer is a Timer that fires when the test calls fire.
+type manualTimer struct {
+	c       chan time.Time
+	started chan time.Duration
+}
+
+func newManualTimer() *manualTimer {
+	return &manualTimer{
+		c:       make(chan time.Time, 1),
+		started: make(chan time.Duration, 100),
+	}
+}
+
+func (t *manualTimer) Start(duration time.Duration) {
+	t.started <- duration
+}
+
+func (t *manualTimer) Stop() {}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) fire() {
+	t.c <- time.Time{}
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue[string](func() BackOff { return &ZeroBackOff{} }, nil, nil)
+	defer q.ShutDown()
+
+	q.Add("a")
+	q.Add("b")
+	q.Add("a")
+	if q.Len() != 2 {
+		t.Fatalf("items are not de-duplicated: %d", q.Len())
+	}
+
+	item, _ := q.Get()
+	if item != "a" {
+		t.Errorf("unexpected item: %s", item)
+	}
+
+	// Adding an item that is being processed queues it after Done.
+	q.Add("a")
+	if q.Len() != 1 {
+		t.Errorf("in-flight item is queued: %d", q.Len())
+	}
+	q.Done("a")
+	if q.Len() != 2 {
+		t.Errorf("item is not queued after Done: %d", q.Len())
+	}
+
+	item, _ = q.Get()
+	q.Done(item)
+	item, _ = q.Get()
+	q.Done(item)
+	if item != "a" || q.Len() != 0 {
+		t.Errorf("unexpected item: %s", item)
+	}
+}
+
+func TestQueueAddRateLimited(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	timer := newManualTimer()
+	q := NewQueue[string](func() BackOff {
+		return WithMaxRetries(NewExponentialBackOff(
+			WithInitialInterval(time.Second),
+			WithRandomizationFactor(0),
+			WithMultiplier(2),
+			WithClockProvider(clock),
+		), 2)
+	}, clock, timer)
+	defer q.ShutDown()
+
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second} {
+		if !q.AddRateLimited("a") {
+			t.Fatal("policy stopped unexpectedly")
+		}
+		if d := <-timer.started; d != expected {
+			t.Errorf("got delay %s, expected %s", d, expected)
+		}
+		if q.Len() != 0 {
+			t.Errorf("item is queued before its delay")
+		}
+
+		clock.Advance(expected)
+		timer.fire()
+		item, _ := q.Get()
+		if item != "a" {
+			t.Errorf("unexpected item: %s", item)
+		}
+		q.Done(item)
+	}
+
+	if q.AddRateLimited("a") {
+		t.Error("item is added after its policy stopped")
+	}
+
+	q.Forget("a")
+	q.AddRateLimited("a")
+	if d := <-timer.started; d != time.Second {
+		t.Errorf("policy is not reset by Forget, got delay %s", d)
+	}
+}
+
+func TestQueueAddAfterKeepsEarlier(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	timer := newManualTimer()
+	q := NewQueue[string](nil, clock, timer)
+	defer q.ShutDown()
+
+	q.AddAfter("a", 10*time.Second)
+	<-timer.started
+	q.AddAfter("b", 5*time.Second)
+	<-timer.started
+	q.AddAfter("a", time.Second)
+	if d := <-timer.started; d != time.Second {
+		t.Errorf("got delay %s, expected 1s", d)
+	}
+
+	clock.Advance(time.Second)
+	timer.fire()
+	if item, _ := q.Get(); item != "a" {
+		t.Errorf("unexpected item: %s", item)
+	}
+	if d := <-timer.started; d != 4*time.Second {
+		t.Errorf("got delay %s, expected 4s", d)
+	}
+}
+
+func TestQueueShutDown(t *testing.T) {
+	q := NewQueue[string](nil, nil, nil)
+
+	q.Add("a")
+	q.AddAfter("b", time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		item, shutdown := q.Get()
+		if item != "a" || shutdown {
+			t.Errorf("queued item is not returned: %q, %t", item, shutdown)
+		}
+		_, shutdown = q.Get()
+		if !shutdown {
+			t.Error("shutdown is not reported")
+		}
+	}()
+
+	q.ShutDown()
+	<-done
+
+	q.Add("c")
+	if q.Len() != 0 || !q.ShuttingDown() {
+		t.Error("item is added after shutdown")
+	}
+	q.ShutDown()
+}
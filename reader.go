@@ -0,0 +1,66 @@
+package backoff
+
+import "io"
+
+// ResumableReader is an io.Reader that reads from a stream opened at an
+// offset, for example an HTTP download with a Range header or a file. When
+// reading fails, the stream is reopened at the current offset after the delay
+// returned by the backoff policy, so a download that fails mid-stream does not
+// restart from zero. A read error is returned only when the policy stops.
+//
+// The policy is reset on each call to Read.
+//
+// Note: Implementation is not thread-safe.
+type ResumableReader struct {
+	open   func(offset int64) (io.ReadCloser, error)
+	b      BackOff
+	rc     io.ReadCloser
+	offset int64
+}
+
+// NewResumableReader returns a ResumableReader that opens streams with open,
+// starting at offset 0, and retries with b.
+func NewResumableReader(open func(offset int64) (io.ReadCloser, error), b BackOff) *ResumableReader {
+	return &ResumableReader{open: open, b: b}
+}
+
+// Offset returns the number of bytes read so far.
+func (r *ResumableReader) Offset() int64 {
+	return r.offset
+}
+
+func (r *ResumableReader) Read(p []byte) (int, error) {
+	return RetryWithData(func() (int, error) {
+		if r.rc == nil {
+			rc, err := r.open(r.offset)
+			if err != nil {
+				return 0, err
+			}
+			r.rc = rc
+		}
+
+		n, err := r.rc.Read(p)
+		r.offset += int64(n)
+		if err == nil || err == io.EOF {
+			return n, Permanent(err)
+		}
+
+		// Reopen on the next attempt or the next call.
+		r.rc.Close()
+		r.rc = nil
+		if n > 0 {
+			return n, nil
+		}
+		return 0, err
+	}, r.b)
+}
+
+// Close closes the current stream.
+func (r *ResumableReader) Close() error {
+	if r.rc == nil {
+		return nil
+	}
+	err := r.rc.Close()
+	r.rc = nil
+	return err
+}
@@ -0,0 +1,101 @@
+package backoff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingReader fails after n bytes.
+type failingReader struct {
+	io.ReadCloser
+	n int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.n -= n
+	return n, err
+}
+
+func TestResumableReader(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "data", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	var offsets []int64
+	open := func(offset int64) (io.ReadCloser, error) {
+		offsets = append(offsets, offset)
+		if len(offsets) == 3 {
+			return nil, errors.New("connection refused")
+		}
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusPartialContent {
+			resp.Body.Close()
+			return nil, Permanent(fmt.Errorf("unexpected status: %s", resp.Status))
+		}
+		// The first two connections drop mid-stream.
+		if len(offsets) < 3 {
+			return &failingReader{ReadCloser: resp.Body, n: 3000}, nil
+		}
+		return resp.Body, nil
+	}
+
+	r := NewResumableReader(open, &ZeroBackOff{})
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(data) != content {
+		t.Errorf("invalid content: %d bytes", len(data))
+	}
+	if r.Offset() != int64(len(content)) {
+		t.Errorf("invalid offset: %d", r.Offset())
+	}
+
+	expected := []int64{0, 3000, 6000, 6000}
+	if fmt.Sprint(offsets) != fmt.Sprint(expected) {
+		t.Errorf("got offsets %v, expected %v", offsets, expected)
+	}
+}
+
+func TestResumableReaderGiveUp(t *testing.T) {
+	var opens int
+	open := func(offset int64) (io.ReadCloser, error) {
+		opens++
+		return io.NopCloser(&failingReader{ReadCloser: io.NopCloser(bytes.NewReader(nil))}), nil
+	}
+
+	r := NewResumableReader(open, WithMaxRetries(&ZeroBackOff{}, 2))
+	if _, err := r.Read(make([]byte, 10)); err != io.ErrUnexpectedEOF {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if opens != 3 {
+		t.Errorf("invalid number of opens: %d", opens)
+	}
+}
@@ -0,0 +1,118 @@
+package backoff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Recorder is a wrapper around another BackOff, which logs each result of
+NextBackOff() and each call to Reset() to an io.Writer, one record per line:
+
+	2024-01-02T15:04:05.123456789Z reset
+	2024-01-02T15:04:05.223456789Z next 500000000
+	2024-01-02T15:04:06.123456789Z next stop
+
+Timestamps are in UTC and delays are in nanoseconds.
+Use NewReplay to return the recorded delays in a test.
+
+Note: Implementation is not thread-safe.
+*/
+type Recorder struct {
+	delegate BackOff
+	w        io.Writer
+	clock    Clock
+	err      error
+}
+
+// NewRecorder returns a Recorder that logs the calls to b to w with
+// timestamps from clock. SystemClock is used when nil is passed.
+func NewRecorder(b BackOff, w io.Writer, clock Clock) *Recorder {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &Recorder{delegate: b, w: w, clock: clock}
+}
+
+func (r *Recorder) NextBackOff() time.Duration {
+	next := r.delegate.NextBackOff()
+	if next == Stop {
+		r.record("next stop")
+	} else {
+		r.record("next " + strconv.FormatInt(int64(next), 10))
+	}
+	return next
+}
+
+func (r *Recorder) Reset() {
+	r.delegate.Reset()
+	r.record("reset")
+}
+
+func (r *Recorder) record(s string) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = fmt.Fprintf(r.w, "%s %s\n", r.clock.Now().UTC().Format(time.RFC3339Nano), s)
+}
+
+// Err returns the first error that occurred while writing the log.
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+// Replay is a backoff policy that returns the delays logged by a Recorder,
+// in the same order. It returns Stop after the last recorded delay.
+// Reset() does not rewind the log, so the whole recorded sequence, including
+// the delays after recorded resets, is replayed once.
+type Replay struct {
+	delays []time.Duration
+	pos    int
+}
+
+// NewReplay reads a log written by a Recorder from r.
+func NewReplay(r io.Reader) (*Replay, error) {
+	var delays []time.Duration
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339Nano, fields[0]); err != nil {
+			return nil, fmt.Errorf("backoff: invalid record on line %d: %w", line, err)
+		}
+		switch {
+		case len(fields) == 2 && fields[1] == "reset":
+		case len(fields) == 3 && fields[1] == "next" && fields[2] == "stop":
+			delays = append(delays, Stop)
+		case len(fields) == 3 && fields[1] == "next":
+			d, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("backoff: invalid record on line %d: %w", line, err)
+			}
+			delays = append(delays, time.Duration(d))
+		default:
+			return nil, fmt.Errorf("backoff: invalid record on line %d: %q", line, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &Replay{delays: delays}, nil
+}
+
+func (r *Replay) NextBackOff() time.Duration {
+	if r.pos >= len(r.delays) {
+		return Stop
+	}
+	d := r.delays[r.pos]
+	r.pos++
+	return d
+}
+
+func (r *Replay) Reset() {}
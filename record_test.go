@@ -0,0 +1,57 @@
+package backoff
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordReplay(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}
+	var log bytes.Buffer
+	rec := NewRecorder(WithMaxRetries(NewExponentialBackOff(), 2), &log, clock)
+
+	var recorded []time.Duration
+	_ = RetryNotifyWithTimer(func() error {
+		clock.Advance(time.Second)
+		return errors.New("error")
+	}, rec, func(err error, next time.Duration) {
+		recorded = append(recorded, next)
+	}, &testTimer{})
+	if rec.Err() != nil {
+		t.Fatalf("unexpected error: %s", rec.Err())
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("invalid log:\n%s", log.String())
+	}
+	if lines[0] != "2024-01-02T15:04:05Z reset" || lines[3] != "2024-01-02T15:04:08Z next stop" {
+		t.Errorf("invalid log:\n%s", log.String())
+	}
+
+	replay, err := NewReplay(&log)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	replay.Reset()
+	for _, expected := range recorded {
+		assertEquals(t, expected, replay.NextBackOff())
+	}
+	assertEquals(t, Stop, replay.NextBackOff())
+	assertEquals(t, Stop, replay.NextBackOff())
+}
+
+func TestReplayInvalid(t *testing.T) {
+	for _, log := range []string{
+		"yesterday next 1\n",
+		"2024-01-02T15:04:05Z next soon\n",
+		"2024-01-02T15:04:05Z wait 1\n",
+	} {
+		if _, err := NewReplay(strings.NewReader(log)); err == nil {
+			t.Errorf("error is unexpectedly nil for %q", log)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// Registry keeps a separate backoff policy per key, for example per remote
+// host, and tells when a key may next be tried.
+//
+// Policies are created lazily from a factory and are evicted after they
+// have not been used for TTL. A key without a policy may be tried at once.
+//
+// Registry is safe for concurrent use.
+type Registry[K comparable] struct {
+	// New creates the policy for a key. It is called when a key fails for the
+	// first time or for the first time after it was evicted.
+	New func() BackOff
+	// TTL is the idle time after which a key is evicted.
+	// Keys are never evicted if TTL == 0.
+	TTL   time.Duration
+	Clock Clock
+
+	mu        sync.Mutex
+	entries   map[K]*registryEntry
+	lastSweep time.Time
+}
+
+type registryEntry struct {
+	b        BackOff
+	next     time.Time
+	stopped  bool
+	lastUsed time.Time
+}
+
+// NewRegistry creates a Registry that creates policies with factory and
+// evicts keys that have been idle for ttl.
+func NewRegistry[K comparable](factory func() BackOff, ttl time.Duration) *Registry[K] {
+	return &Registry[K]{
+		New:   factory,
+		TTL:   ttl,
+		Clock: SystemClock,
+	}
+}
+
+// Failure records a failure for key and returns the duration to wait before
+// trying it again, or Stop if the policy of key stopped.
+func (r *Registry[K]) Failure(key K) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	e, ok := r.entries[key]
+	if !ok {
+		if r.entries == nil {
+			r.entries = make(map[K]*registryEntry)
+		}
+		e = &registryEntry{b: r.New()}
+		e.b.Reset()
+		r.entries[key] = e
+	}
+	e.lastUsed = now
+
+	next := e.b.NextBackOff()
+	if next == Stop {
+		e.stopped = true
+		return Stop
+	}
+	e.next = now.Add(next)
+	return next
+}
+
+// Success forgets the policy of key, so it may be tried at once.
+func (r *Registry[K]) Success(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now()
+	delete(r.entries, key)
+}
+
+// When returns the time key may next be tried. ok is false if the policy of
+// key stopped, meaning that it should not be tried again until Success is called
+// or it is evicted.
+func (r *Registry[K]) When(key K) (t time.Time, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	e, found := r.entries[key]
+	if !found {
+		return now, true
+	}
+	if e.stopped {
+		return time.Time{}, false
+	}
+	if e.next.Before(now) {
+		return now, true
+	}
+	return e.next, true
+}
+
+// Len returns the number of keys with a policy.
+func (r *Registry[K]) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now()
+	return len(r.entries)
+}
+
+// now returns the current time and evicts idle keys if needed.
+func (r *Registry[K]) now() time.Time {
+	clock := r.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now()
+	if r.TTL > 0 && now.Sub(r.lastSweep) >= r.TTL {
+		r.sweep(now)
+	}
+	return now
+}
+
+func (r *Registry[K]) sweep(now time.Time) {
+	if r.TTL == 0 {
+		return
+	}
+	r.lastSweep = now
+	for key, e := range r.entries {
+		// Keys that are still backing off are kept even if they are idle.
+		if now.Sub(e.lastUsed) >= r.TTL && !e.next.After(now) {
+			delete(r.entries, key)
+		}
+	}
+}
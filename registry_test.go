@@ -0,0 +1,88 @@
+package backoff
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock that only moves when it is advanced.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestRegistry(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	r := NewRegistry[string](func() BackOff {
+		return WithMaxRetries(NewConstantBackOff(time.Second), 2)
+	}, time.Minute)
+	r.Clock = clock
+
+	if when, ok := r.When("a"); !ok || !when.Equal(clock.Now()) {
+		t.Errorf("unknown key must be tried at once, got %s, %t", when, ok)
+	}
+
+	assertEquals(t, time.Second, r.Failure("a"))
+	if when, ok := r.When("a"); !ok || !when.Equal(clock.Now().Add(time.Second)) {
+		t.Errorf("invalid next time: %s, %t", when, ok)
+	}
+	if when, _ := r.When("b"); !when.Equal(clock.Now()) {
+		t.Errorf("keys must be independent, got %s", when)
+	}
+
+	clock.Advance(2 * time.Second)
+	if when, ok := r.When("a"); !ok || !when.Equal(clock.Now()) {
+		t.Errorf("key must be tried at once after its delay, got %s, %t", when, ok)
+	}
+
+	assertEquals(t, time.Second, r.Failure("a"))
+	assertEquals(t, Stop, r.Failure("a"))
+	if _, ok := r.When("a"); ok {
+		t.Error("stopped key must not be tried")
+	}
+
+	r.Success("a")
+	if when, ok := r.When("a"); !ok || !when.Equal(clock.Now()) {
+		t.Errorf("key must be tried at once after success, got %s, %t", when, ok)
+	}
+	if r.Len() != 0 {
+		t.Errorf("invalid number of keys: %d", r.Len())
+	}
+}
+
+func TestRegistryEviction(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	r := NewRegistry[string](func() BackOff {
+		return NewConstantBackOff(time.Second)
+	}, time.Minute)
+	r.Clock = clock
+
+	r.Failure("a")
+	r.Failure("b")
+	clock.Advance(30 * time.Second)
+	r.Failure("b")
+	if r.Len() != 2 {
+		t.Errorf("invalid number of keys: %d", r.Len())
+	}
+
+	clock.Advance(40 * time.Second)
+	if r.Len() != 1 {
+		t.Errorf("idle key is not evicted, number of keys: %d", r.Len())
+	}
+	if _, ok := r.entries["b"]; !ok {
+		t.Error("used key is evicted")
+	}
+}
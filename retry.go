@@ -2,6 +2,7 @@ package backoff
 
 import (
 	"errors"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,11 +20,67 @@ func (o Operation) withEmptyData() OperationWithData[struct{}] {
 	}
 }
 
+// Attempt describes a single call of an operation made by the retry loop.
+type Attempt struct {
+	// Number is the 1-based number of the attempt.
+	Number int
+	// Start is the time the attempt started.
+	Start time.Time
+	// Elapsed is the time passed since the first attempt started.
+	Elapsed time.Duration
+	// PrevErr is the error returned by the previous attempt.
+	// It is nil on the first attempt.
+	PrevErr error
+	// PrevDelay is the delay waited before the attempt.
+	// It is zero on the first attempt.
+	PrevDelay time.Duration
+
+	progress *int32
+}
+
+// Progress reports that the attempt made meaningful progress, for example a
+// streaming subscription delivered messages. If the attempt fails afterwards,
+// the backoff policy is reset before the next delay is computed, so a long
+// healthy attempt is not followed by the delay grown by earlier failures.
+//
+// Progress may be called from any goroutine while the attempt is running.
+func (a Attempt) Progress() {
+	if a.progress != nil {
+		atomic.StoreInt32(a.progress, 1)
+	}
+}
+
+func (a Attempt) progressed() bool {
+	return a.progress != nil && atomic.LoadInt32(a.progress) == 1
+}
+
+// An OperationWithAttempt is executing by RetryWithAttempt().
+// It receives the metadata of the current attempt.
+type OperationWithAttempt func(Attempt) error
+
+// An OperationWithAttemptAndData is executing by RetryWithAttemptAndData() or
+// RetryNotifyWithTimerAndAttempt().
+// It receives the metadata of the current attempt.
+type OperationWithAttemptAndData[T any] func(Attempt) (T, error)
+
+func (o OperationWithData[T]) withAttempt() OperationWithAttemptAndData[T] {
+	return func(Attempt) (T, error) {
+		return o()
+	}
+}
+
+func (o OperationWithAttempt) withEmptyData() OperationWithAttemptAndData[struct{}] {
+	return func(a Attempt) (struct{}, error) {
+		return struct{}{}, o(a)
+	}
+}
+
 // Notify is a notify-on-error function. It receives an operation error and
 // backoff delay if the operation failed (with an error).
 //
 // NOTE that if the backoff policy stated to stop retrying,
-// the notify function isn't called.
+// the notify function isn't called. Use an Observer with RetryObserve
+// to be notified of every event of the retry loop.
 type Notify func(error, time.Duration)
 
 // Retry the operation o until it does not return error or BackOff stops.
@@ -39,8 +96,22 @@ func Retry(o Operation, b BackOff) error {
 }
 
 // RetryWithData is like Retry but returns data in the response too.
-func RetryWithData[T any](o OperationWithData[T], b BackOff) (T, error) {
-	return RetryNotifyWithData(o, b, nil)
+// See RetryOption for the options it accepts.
+func RetryWithData[T any](o OperationWithData[T], b BackOff, opts ...RetryOption[T]) (T, error) {
+	res, err := RetryNotifyWithData(o, b, nil)
+	return applyRetryOptions(res, err, opts)
+}
+
+// RetryWithAttempt is like Retry but passes the metadata of the current
+// attempt to the operation.
+func RetryWithAttempt(o OperationWithAttempt, b BackOff) error {
+	_, err := doRetryNotify(o.withEmptyData(), b, nil, nil)
+	return err
+}
+
+// RetryWithAttemptAndData is like RetryWithAttempt but returns data in the response too.
+func RetryWithAttemptAndData[T any](o OperationWithAttemptAndData[T], b BackOff) (T, error) {
+	return doRetryNotify(o, b, nil, nil)
 }
 
 // RetryNotify calls notify function with the error and wait duration
@@ -51,31 +122,59 @@ func RetryNotify(operation Operation, b BackOff, notify Notify) error {
 
 // RetryNotifyWithData is like RetryNotify but returns data in the response too.
 func RetryNotifyWithData[T any](operation OperationWithData[T], b BackOff, notify Notify) (T, error) {
-	return doRetryNotify(operation, b, notify, nil)
+	return doRetryNotify(operation.withAttempt(), b, observerFromNotify(notify), nil)
 }
 
 // RetryNotifyWithTimer calls notify function with the error and wait duration using the given Timer
 // for each failed attempt before sleep.
 // A default timer that uses system timer is used when nil is passed.
 func RetryNotifyWithTimer(operation Operation, b BackOff, notify Notify, t Timer) error {
-	_, err := doRetryNotify(operation.withEmptyData(), b, notify, t)
+	_, err := doRetryNotify(operation.withEmptyData().withAttempt(), b, observerFromNotify(notify), t)
 	return err
 }
 
 // RetryNotifyWithTimerAndData is like RetryNotifyWithTimer but returns data in the response too.
 func RetryNotifyWithTimerAndData[T any](operation OperationWithData[T], b BackOff, notify Notify, t Timer) (T, error) {
-	return doRetryNotify(operation, b, notify, t)
+	return doRetryNotify(operation.withAttempt(), b, observerFromNotify(notify), t)
+}
+
+// RetryNotifyWithTimerAndAttempt is like RetryNotifyWithTimerAndData but passes
+// the metadata of the current attempt to the operation.
+func RetryNotifyWithTimerAndAttempt[T any](operation OperationWithAttemptAndData[T], b BackOff, notify Notify, t Timer) (T, error) {
+	return doRetryNotify(operation, b, observerFromNotify(notify), t)
+}
+
+// RetryObserve is like Retry but reports every event of the retry loop to obs.
+func RetryObserve(o Operation, b BackOff, obs Observer) error {
+	_, err := doRetryNotify(o.withEmptyData().withAttempt(), b, obs, nil)
+	return err
+}
+
+// RetryObserveWithData is like RetryObserve but returns data in the response too.
+func RetryObserveWithData[T any](o OperationWithData[T], b BackOff, obs Observer) (T, error) {
+	return doRetryNotify(o.withAttempt(), b, obs, nil)
 }
 
-func doRetryNotify[T any](operation OperationWithData[T], b BackOff, notify Notify, t Timer) (T, error) {
+// RetryObserveWithTimerAndAttempt is like RetryObserveWithData but uses the
+// given Timer and passes the metadata of the current attempt to the operation.
+// A default timer that uses system timer is used when nil is passed.
+func RetryObserveWithTimerAndAttempt[T any](o OperationWithAttemptAndData[T], b BackOff, obs Observer, t Timer) (T, error) {
+	return doRetryNotify(o, b, obs, t)
+}
+
+func doRetryNotify[T any](operation OperationWithAttemptAndData[T], b BackOff, obs Observer, t Timer) (T, error) {
 	var (
-		err  error
-		next time.Duration
-		res  T
+		err     error
+		next    time.Duration
+		res     T
+		attempt Attempt
 	)
 	if t == nil {
 		t = &defaultTimer{}
 	}
+	if obs == nil {
+		obs = NopObserver{}
+	}
 
 	defer func() {
 		t.Stop()
@@ -84,36 +183,55 @@ func doRetryNotify[T any](operation OperationWithData[T], b BackOff, notify Noti
 	ctx := getContext(b)
 
 	b.Reset()
+	var first time.Time
 	for {
-		res, err = operation()
+		attempt.Number++
+		attempt.Start = time.Now()
+		if attempt.Number == 1 {
+			first = attempt.Start
+		}
+		attempt.Elapsed = attempt.Start.Sub(first)
+		attempt.progress = new(int32)
+		obs.OnAttemptStart(attempt)
+		res, err = operation(attempt)
 		if err == nil {
+			obs.OnSuccess(attempt.Number, time.Since(first))
 			return res, nil
 		}
+		obs.OnAttemptFailure(attempt, err)
 
 		var permanent *PermanentError
 		if errors.As(err, &permanent) {
+			obs.OnGiveUp(attempt.Number, time.Since(first), permanent.Err, ReasonPermanent)
 			return res, permanent.Err
 		}
 
+		if attempt.progressed() {
+			b.Reset()
+		}
 		if next = b.NextBackOff(); next == Stop {
 			if cerr := ctx.Err(); cerr != nil {
+				obs.OnGiveUp(attempt.Number, time.Since(first), cerr, ReasonContext)
 				return res, cerr
 			}
 
+			obs.OnGiveUp(attempt.Number, time.Since(first), err, ReasonBackOff)
 			return res, err
 		}
 
-		if notify != nil {
-			notify(err, next)
-		}
+		obs.OnWait(attempt, err, next)
 
 		t.Start(next)
 
 		select {
 		case <-ctx.Done():
+			obs.OnGiveUp(attempt.Number, time.Since(first), ctx.Err(), ReasonContext)
 			return res, ctx.Err()
 		case <-t.C():
 		}
+
+		attempt.PrevErr = err
+		attempt.PrevDelay = next
 	}
 }
 
@@ -222,3 +222,79 @@ func TestPermanent(t *testing.T) {
 		t.Errorf("got %v, want nil", err)
 	}
 }
+
+func TestRetryWithAttempt(t *testing.T) {
+	const successOn = 3
+	var attempts []Attempt
+
+	f := func(a Attempt) (int, error) {
+		attempts = append(attempts, a)
+		if a.Number == successOn {
+			return 42, nil
+		}
+		return 0, fmt.Errorf("error (%d)", a.Number)
+	}
+
+	res, err := RetryNotifyWithTimerAndAttempt(f, NewConstantBackOff(time.Millisecond), nil, &testTimer{})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if res != 42 {
+		t.Errorf("invalid data in response: %d, expected 42", res)
+	}
+	if len(attempts) != successOn {
+		t.Fatalf("invalid number of retries: %d", len(attempts))
+	}
+
+	first := attempts[0]
+	if first.Number != 1 || first.PrevErr != nil || first.PrevDelay != 0 || first.Elapsed != 0 {
+		t.Errorf("unexpected first attempt: %+v", first)
+	}
+	for i, a := range attempts[1:] {
+		prev := attempts[i]
+		if a.Number != prev.Number+1 {
+			t.Errorf("invalid attempt number: %d", a.Number)
+		}
+		if a.PrevErr == nil || a.PrevErr.Error() != fmt.Sprintf("error (%d)", prev.Number) {
+			t.Errorf("invalid previous error: %v", a.PrevErr)
+		}
+		if a.PrevDelay != time.Millisecond {
+			t.Errorf("invalid previous delay: %s", a.PrevDelay)
+		}
+		if a.Start.Before(prev.Start) || a.Elapsed < prev.Elapsed {
+			t.Errorf("attempt times are not increasing: %+v", a)
+		}
+	}
+}
+
+func TestRetryProgress(t *testing.T) {
+	var delays []time.Duration
+	notify := func(err error, next time.Duration) {
+		delays = append(delays, next)
+	}
+
+	f := func(a Attempt) (struct{}, error) {
+		switch a.Number {
+		case 3:
+			// A long attempt that delivered messages before failing.
+			a.Progress()
+		case 5:
+			return struct{}{}, nil
+		}
+		return struct{}{}, errors.New("error")
+	}
+
+	b := NewExponentialBackOff(WithInitialInterval(time.Second), WithRandomizationFactor(0), WithMultiplier(2))
+	_, err := RetryNotifyWithTimerAndAttempt(f, b, notify, &testTimer{})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, time.Second, 2 * time.Second}
+	if len(delays) != len(expected) {
+		t.Fatalf("got delays %v, expected %v", delays, expected)
+	}
+	for i := range delays {
+		assertEquals(t, expected[i], delays[i])
+	}
+}
@@ -0,0 +1,189 @@
+package backoff
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Scheduler drives many Timers from a single goroutine and a single
+// time.Timer, keeping pending timers in a min-heap. Use it instead of the
+// default timer when running a large number of concurrent retries:
+//
+//	s := backoff.NewScheduler()
+//	defer s.Stop()
+//	err := backoff.RetryNotifyWithTimer(operation, b, nil, s.NewTimer())
+//
+// Scheduler is safe for concurrent use.
+type Scheduler struct {
+	mu     sync.Mutex
+	timers schedulerHeap
+	closed bool
+
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewScheduler returns a new Scheduler. Stop must be called to release its
+// goroutine when it is not used anymore.
+func NewScheduler() *Scheduler {
+	s := &Scheduler{
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// NewTimer returns a Timer driven by s. Each call to the Retry functions or
+// NewTickerWithTimer needs its own Timer.
+func (s *Scheduler) NewTimer() Timer {
+	return &schedulerTimer{s: s, c: make(chan time.Time, 1), index: -1}
+}
+
+// Stop stops the goroutine of s. Timers that have not fired yet fire at once,
+// and so do timers started after Stop, so that retries waiting for them
+// are not blocked.
+func (s *Scheduler) Stop() {
+	s.once.Do(func() { close(s.stop) })
+	<-s.stopped
+}
+
+func (s *Scheduler) start(t *schedulerTimer, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Discard a tick that was not received, like time.Timer.Reset does.
+	select {
+	case <-t.c:
+	default:
+	}
+
+	if s.closed {
+		t.c <- time.Now()
+		return
+	}
+
+	t.when = time.Now().Add(d)
+	if t.index < 0 {
+		heap.Push(&s.timers, t)
+	} else {
+		heap.Fix(&s.timers, t.index)
+	}
+	if t.index == 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Scheduler) remove(t *schedulerTimer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.index >= 0 {
+		heap.Remove(&s.timers, t.index)
+	}
+}
+
+func (s *Scheduler) run() {
+	defer close(s.stopped)
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		now := time.Now()
+		for len(s.timers) > 0 && !s.timers[0].when.After(now) {
+			t := heap.Pop(&s.timers).(*schedulerTimer)
+			select {
+			case t.c <- now:
+			default:
+			}
+		}
+		var afterC <-chan time.Time
+		if len(s.timers) > 0 {
+			timer.Reset(s.timers[0].when.Sub(now))
+			afterC = timer.C
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-afterC:
+		case <-s.wake:
+		case <-s.stop:
+			s.close()
+			return
+		}
+	}
+}
+
+// close fires all pending timers and makes timers fire at once from now on.
+func (s *Scheduler) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	now := time.Now()
+	for len(s.timers) > 0 {
+		t := heap.Pop(&s.timers).(*schedulerTimer)
+		select {
+		case t.c <- now:
+		default:
+		}
+	}
+}
+
+// schedulerTimer implements Timer interface using a Scheduler.
+type schedulerTimer struct {
+	s     *Scheduler
+	c     chan time.Time
+	when  time.Time
+	index int // index in the heap of the scheduler, -1 if not scheduled
+}
+
+// C returns the timers channel which receives the current time when the timer fires.
+func (t *schedulerTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Start starts the timer to fire after the given duration
+func (t *schedulerTimer) Start(duration time.Duration) {
+	t.s.start(t, duration)
+}
+
+// Stop is called when the timer is not used anymore and resources may be freed.
+func (t *schedulerTimer) Stop() {
+	t.s.remove(t)
+}
+
+// schedulerHeap is a min-heap of timers ordered by the time they fire.
+type schedulerHeap []*schedulerTimer
+
+func (h schedulerHeap) Len() int           { return len(h) }
+func (h schedulerHeap) Less(i, j int) bool { return h[i].when.Before(h[j].when) }
+
+func (h schedulerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *schedulerHeap) Push(x interface{}) {
+	t := x.(*schedulerTimer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *schedulerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
@@ -0,0 +1,152 @@
+package backoff
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	const successOn = 3
+	var i = 0
+
+	f := func() error {
+		i++
+		if i == successOn {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	err := RetryNotifyWithTimer(f, NewConstantBackOff(time.Millisecond), nil, s.NewTimer())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if i != successOn {
+		t.Errorf("invalid number of retries: %d", i)
+	}
+}
+
+// scheduled returns the timers of s in the order they fire.
+func (s *Scheduler) scheduled() []*schedulerTimer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	timers := append([]*schedulerTimer(nil), s.timers...)
+	sort.Slice(timers, func(i, j int) bool { return timers[i].when.Before(timers[j].when) })
+	return timers
+}
+
+func TestSchedulerOrder(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	late := s.NewTimer()
+	early := s.NewTimer()
+	stopped := s.NewTimer()
+	late.Start(2 * time.Hour)
+	early.Start(time.Hour)
+	stopped.Start(time.Minute)
+	stopped.Stop()
+
+	timers := s.scheduled()
+	if len(timers) != 2 || timers[0] != early || timers[1] != late {
+		t.Fatalf("invalid order of timers: %v", timers)
+	}
+
+	// Restarting a timer reschedules it.
+	late.Start(time.Minute)
+	if timers = s.scheduled(); len(timers) != 2 || timers[0] != late || timers[1] != early {
+		t.Fatalf("invalid order of timers after restart: %v", timers)
+	}
+
+	late.Start(0)
+	early.Start(0)
+	<-late.C()
+	<-early.C()
+	if timers = s.scheduled(); len(timers) != 0 {
+		t.Errorf("fired timers are still scheduled: %v", timers)
+	}
+	select {
+	case <-stopped.C():
+		t.Error("stopped timer fired")
+	default:
+	}
+}
+
+func TestSchedulerStopWaitingRetry(t *testing.T) {
+	s := NewScheduler()
+
+	waiting := make(chan struct{}, 1)
+	notify := func(error, time.Duration) {
+		select {
+		case waiting <- struct{}{}:
+		default:
+		}
+	}
+	done := make(chan error)
+	go func() {
+		f := func() error { return errors.New("error") }
+		done <- RetryNotifyWithTimer(f, WithMaxRetries(NewConstantBackOff(time.Hour), 3), notify, s.NewTimer())
+	}()
+
+	// Timers of a stopped scheduler fire at once, so the retry runs out of tries.
+	<-waiting
+	s.Stop()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("error is unexpectedly nil")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("retry is blocked after Stop")
+	}
+}
+
+func TestSchedulerTicker(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	ticker := NewTickerWithTimer(WithMaxRetries(NewConstantBackOff(time.Millisecond), 3), s.NewTimer())
+	var ticks int
+	for range ticker.C {
+		ticks++
+	}
+	if ticks != 4 {
+		t.Errorf("invalid number of ticks: %d", ticks)
+	}
+}
+
+const benchmarkConcurrentWaits = 10000
+
+func benchmarkTimers(b *testing.B, newTimer func() Timer) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		var wg sync.WaitGroup
+		for i := 0; i < benchmarkConcurrentWaits; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				t := newTimer()
+				defer t.Stop()
+				t.Start(time.Duration(i%100) * time.Microsecond)
+				<-t.C()
+			}(i)
+		}
+		wg.Wait()
+	}
+}
+
+func BenchmarkDefaultTimer(b *testing.B) {
+	benchmarkTimers(b, func() Timer { return &defaultTimer{} })
+}
+
+func BenchmarkScheduler(b *testing.B) {
+	s := NewScheduler()
+	defer s.Stop()
+	benchmarkTimers(b, s.NewTimer)
+}
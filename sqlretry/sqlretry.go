@@ -0,0 +1,108 @@
+// Package sqlretry retries database/sql transactions that fail because of
+// serialization failures or deadlocks.
+//
+// Retrying such a transaction means running the whole function again in a new
+// transaction, which InTx does:
+//
+//	err := sqlretry.InTx(ctx, db, nil, func(tx *sql.Tx) error {
+//		// Read and write using tx.
+//		return nil
+//	})
+package sqlretry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// RetryableCodes are the SQLSTATE codes IsRetryable matches:
+// serialization_failure and deadlock_detected.
+var RetryableCodes = []string{"40001", "40P01"}
+
+// Options configures InTx. The zero value and nil use the defaults.
+type Options struct {
+	// NewBackOff creates the policy for each call of InTx.
+	// backoff.NewExponentialBackOff is used if it is nil.
+	NewBackOff func() backoff.BackOff
+	// TxOptions is passed to sql.DB.BeginTx.
+	TxOptions *sql.TxOptions
+	// Retryable reports whether an error is worth running the transaction again.
+	// IsRetryable is used if it is nil.
+	Retryable func(error) bool
+}
+
+// SQLState returns the SQLSTATE code of err. It finds errors that have a
+// SQLState() string method, like the errors of the pgx and lib/pq drivers.
+func SQLState(err error) (string, bool) {
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState(), true
+	}
+	return "", false
+}
+
+// IsRetryable reports whether the SQLSTATE code of err is in RetryableCodes.
+func IsRetryable(err error) bool {
+	code, ok := SQLState(err)
+	if !ok {
+		return false
+	}
+	for _, c := range RetryableCodes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+// InTx runs fn in a transaction and commits it. If fn, beginning or committing
+// fails, the transaction is rolled back and, if the error is retryable, fn is
+// run again in a new transaction after the delay returned by the backoff policy.
+//
+// fn may run several times, so it must not have side effects outside of tx.
+// If fn returns a *backoff.PermanentError, the transaction is not retried.
+// InTx stops when ctx is done.
+func InTx(ctx context.Context, db *sql.DB, opts *Options, fn func(*sql.Tx) error) error {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.NewBackOff == nil {
+		o.NewBackOff = func() backoff.BackOff { return backoff.NewExponentialBackOff() }
+	}
+	if o.Retryable == nil {
+		o.Retryable = IsRetryable
+	}
+
+	return backoff.Retry(func() error {
+		err := run(ctx, db, o.TxOptions, fn)
+		var permanent *backoff.PermanentError
+		if err != nil && !errors.As(err, &permanent) && !o.Retryable(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, backoff.WithContext(o.NewBackOff(), ctx))
+}
+
+// run runs fn in a single transaction.
+func run(ctx context.Context, db *sql.DB, txOpts *sql.TxOptions, fn func(*sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, txOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			_ = tx.Rollback()
+			panic(v)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
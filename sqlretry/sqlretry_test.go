@@ -0,0 +1,181 @@
+package sqlretry
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// stateError is a driver error with a SQLSTATE code.
+type stateError struct {
+	code string
+}
+
+func (e *stateError) Error() string    { return "sqlstate " + e.code }
+func (e *stateError) SQLState() string { return e.code }
+
+// fakeDriver records transactions and fails commits with scripted errors.
+type fakeDriver struct {
+	mu         sync.Mutex
+	begins     int
+	commits    int
+	rollbacks  int
+	commitErrs []error
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.begins++
+	return &fakeTx{d: c.d}, nil
+}
+
+type fakeTx struct {
+	d *fakeDriver
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	if len(tx.d.commitErrs) > 0 {
+		err := tx.d.commitErrs[0]
+		tx.d.commitErrs = tx.d.commitErrs[1:]
+		return err
+	}
+	tx.d.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+var (
+	testDriver     = &fakeDriver{}
+	registerDriver sync.Once
+)
+
+func openDB(t *testing.T, commitErrs ...error) (*sql.DB, *fakeDriver) {
+	registerDriver.Do(func() { sql.Register("sqlretrytest", testDriver) })
+	*testDriver = fakeDriver{commitErrs: commitErrs}
+	db, err := sql.Open("sqlretrytest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, testDriver
+}
+
+var testOptions = &Options{
+	NewBackOff: func() backoff.BackOff { return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 5) },
+}
+
+func TestInTx(t *testing.T) {
+	db, d := openDB(t)
+
+	var runs int
+	err := InTx(context.Background(), db, testOptions, func(tx *sql.Tx) error {
+		runs++
+		switch runs {
+		case 1:
+			return &stateError{"40001"}
+		case 2:
+			return &stateError{"40P01"}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if runs != 3 || d.begins != 3 || d.rollbacks != 2 || d.commits != 1 {
+		t.Errorf("runs: %d, begins: %d, rollbacks: %d, commits: %d", runs, d.begins, d.rollbacks, d.commits)
+	}
+}
+
+func TestInTxCommitFailure(t *testing.T) {
+	db, d := openDB(t, &stateError{"40001"})
+
+	var runs int
+	err := InTx(context.Background(), db, testOptions, func(tx *sql.Tx) error {
+		runs++
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if runs != 2 || d.commits != 1 {
+		t.Errorf("runs: %d, commits: %d", runs, d.commits)
+	}
+}
+
+func TestInTxNotRetryable(t *testing.T) {
+	db, d := openDB(t)
+
+	uniqueViolation := &stateError{"23505"}
+	var runs int
+	err := InTx(context.Background(), db, testOptions, func(tx *sql.Tx) error {
+		runs++
+		return uniqueViolation
+	})
+	if err != uniqueViolation {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if runs != 1 || d.rollbacks != 1 {
+		t.Errorf("runs: %d, rollbacks: %d", runs, d.rollbacks)
+	}
+
+	errFatal := errors.New("fatal")
+	err = InTx(context.Background(), db, testOptions, func(tx *sql.Tx) error {
+		return backoff.Permanent(&stateError{"40001"})
+	})
+	if code, _ := SQLState(err); code != "40001" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	opts := *testOptions
+	opts.Retryable = func(err error) bool { return errors.Is(err, errFatal) }
+	runs = 0
+	err = InTx(context.Background(), db, &opts, func(tx *sql.Tx) error {
+		runs++
+		if runs == 1 {
+			return errFatal
+		}
+		return nil
+	})
+	if err != nil || runs != 2 {
+		t.Errorf("custom predicate is not used: %v, %d runs", err, runs)
+	}
+}
+
+func TestInTxGiveUp(t *testing.T) {
+	db, _ := openDB(t)
+
+	var runs int
+	err := InTx(context.Background(), db, testOptions, func(tx *sql.Tx) error {
+		runs++
+		return &stateError{"40001"}
+	})
+	if !IsRetryable(err) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if runs != 6 {
+		t.Errorf("invalid number of runs: %d", runs)
+	}
+}
@@ -0,0 +1,171 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// PanicError is returned by a child of a Supervisor that panicked.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Supervisor runs long-running functions as children and restarts each of them
+// with its own backoff policy when it returns an error or panics (one-for-one
+// supervision). A child that returns nil is not restarted.
+//
+// The policy of a child is reset when the child ran for at least StablePeriod
+// before failing, so a worker that crashes once a day does not wait for the
+// delay grown by an earlier crash loop.
+//
+// A Supervisor may be created with NewSupervisor or as a struct literal with
+// at least NewBackOff set. The children of a literal run until Stop is called.
+type Supervisor struct {
+	// NewBackOff creates the restart policy of each child.
+	NewBackOff func() BackOff
+	// StablePeriod is the run time after which a child is considered healthy.
+	// Policies are never reset if StablePeriod == 0.
+	StablePeriod time.Duration
+	// OnRestart is called with the error of a child and the delay before it
+	// is restarted, if it is not nil.
+	OnRestart func(name string, err error, next time.Duration)
+	// Clock measures the run time of children.
+	// SystemClock is used if it is nil.
+	Clock Clock
+	// NewTimer creates the timer each child waits with before a restart.
+	// A default timer that uses system timer is used if it is nil.
+	NewTimer func() Timer
+
+	initOnce sync.Once
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewSupervisor returns a Supervisor whose children run until ctx is done
+// or Stop is called.
+func NewSupervisor(ctx context.Context, newBackOff func() BackOff, stablePeriod time.Duration) *Supervisor {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Supervisor{
+		NewBackOff:   newBackOff,
+		StablePeriod: stablePeriod,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// init sets up the context of a Supervisor created as a struct literal.
+func (s *Supervisor) init() {
+	s.initOnce.Do(func() {
+		if s.ctx == nil {
+			s.ctx, s.cancel = context.WithCancel(context.Background())
+		}
+	})
+}
+
+// Go starts fn as a child in a new goroutine.
+func (s *Supervisor) Go(name string, fn func(ctx context.Context) error) {
+	s.init()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.Run(name, fn); err != nil && !errors.Is(err, s.ctx.Err()) {
+			s.mu.Lock()
+			if s.err == nil {
+				s.err = fmt.Errorf("%s: %w", name, err)
+			}
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// Run runs fn as a child in the calling goroutine. It returns nil when fn
+// returns nil, the error of fn when the policy stops or fn returns a
+// *PermanentError, and the context error when the Supervisor is stopped.
+func (s *Supervisor) Run(name string, fn func(ctx context.Context) error) error {
+	s.init()
+	clock := s.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	var t Timer = &defaultTimer{}
+	if s.NewTimer != nil {
+		t = s.NewTimer()
+	}
+	defer t.Stop()
+
+	b := s.NewBackOff()
+	b.Reset()
+	for {
+		start := clock.Now()
+		err := runChild(s.ctx, fn)
+		if err == nil {
+			return nil
+		}
+		if cerr := s.ctx.Err(); cerr != nil {
+			return cerr
+		}
+
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			return permanent.Err
+		}
+
+		if s.StablePeriod > 0 && clock.Now().Sub(start) >= s.StablePeriod {
+			b.Reset()
+		}
+		next := b.NextBackOff()
+		if next == Stop {
+			return err
+		}
+		if s.OnRestart != nil {
+			s.OnRestart(name, err, next)
+		}
+
+		t.Start(next)
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-t.C():
+		}
+	}
+}
+
+// runChild calls fn and converts a panic into a *PanicError.
+func runChild(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx)
+}
+
+// Wait waits for all children started with Go to finish and returns the
+// first error a child stopped with, other than the context error.
+func (s *Supervisor) Wait() error {
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Stop cancels the context of all children and waits for them to return.
+// See Wait for the returned error.
+func (s *Supervisor) Stop() error {
+	s.init()
+	s.cancel()
+	return s.Wait()
+}
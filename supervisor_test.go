@@ -0,0 +1,154 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSupervisorRestart(t *testing.T) {
+	s := NewSupervisor(context.Background(), func() BackOff {
+		return WithMaxRetries(&ZeroBackOff{}, 3)
+	}, 0)
+
+	var runs int
+	var restarts []string
+	s.OnRestart = func(name string, err error, next time.Duration) {
+		restarts = append(restarts, name+": "+err.Error())
+	}
+
+	err := s.Run("worker", func(ctx context.Context) error {
+		runs++
+		switch runs {
+		case 1:
+			return errors.New("error")
+		case 2:
+			panic("boom")
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if runs != 3 {
+		t.Errorf("invalid number of runs: %d", runs)
+	}
+	if len(restarts) != 2 || restarts[0] != "worker: error" || restarts[1] != "worker: panic: boom" {
+		t.Errorf("unexpected restarts: %q", restarts)
+	}
+}
+
+func TestSupervisorGiveUp(t *testing.T) {
+	s := NewSupervisor(context.Background(), func() BackOff {
+		return WithMaxRetries(&ZeroBackOff{}, 2)
+	}, 0)
+
+	var runs int
+	s.Go("failing", func(ctx context.Context) error {
+		runs++
+		return errors.New("error")
+	})
+
+	err := s.Wait()
+	if err == nil || err.Error() != "failing: error" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("invalid number of runs: %d", runs)
+	}
+}
+
+func TestSupervisorStablePeriod(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	s := &Supervisor{
+		NewBackOff: func() BackOff {
+			return NewExponentialBackOff(
+				WithInitialInterval(time.Second),
+				WithRandomizationFactor(0),
+				WithMultiplier(2),
+				WithMaxElapsedTime(0),
+				WithClockProvider(clock),
+			)
+		},
+		StablePeriod: time.Minute,
+		Clock:        clock,
+		NewTimer:     func() Timer { return &testTimer{} },
+	}
+
+	var delays []time.Duration
+	s.OnRestart = func(name string, err error, next time.Duration) {
+		delays = append(delays, next)
+	}
+
+	var runs int
+	err := s.Run("worker", func(ctx context.Context) error {
+		runs++
+		switch runs {
+		case 3:
+			// Run healthily for a while before failing.
+			clock.Advance(time.Minute)
+		case 4:
+			return nil
+		}
+		return errors.New("error")
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, time.Second}
+	if len(delays) != len(expected) {
+		t.Fatalf("got delays %v, expected %v", delays, expected)
+	}
+	for i := range delays {
+		assertEquals(t, expected[i], delays[i])
+	}
+}
+
+func TestSupervisorLiteral(t *testing.T) {
+	s := &Supervisor{
+		NewBackOff: func() BackOff { return WithMaxRetries(&ZeroBackOff{}, 1) },
+	}
+
+	var runs int
+	s.Go("failing", func(ctx context.Context) error {
+		runs++
+		return errors.New("error")
+	})
+	if err := s.Wait(); err == nil || err.Error() != "failing: error" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if runs != 2 {
+		t.Errorf("invalid number of runs: %d", runs)
+	}
+	if err := s.Stop(); err == nil {
+		t.Error("error is unexpectedly nil")
+	}
+}
+
+func TestSupervisorStop(t *testing.T) {
+	s := NewSupervisor(context.Background(), func() BackOff {
+		return NewConstantBackOff(time.Hour)
+	}, 0)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	s.Go("blocking", func(ctx context.Context) error {
+		wg.Done()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var once sync.Once
+	s.Go("waiting", func(ctx context.Context) error {
+		once.Do(wg.Done)
+		return errors.New("error")
+	})
+	wg.Wait()
+
+	if err := s.Stop(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -1,12 +1,22 @@
 package backoff
 
-import "time"
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
 
 /*
 WithMaxRetries creates a wrapper around another BackOff, which will
 return Stop if NextBackOff() has been called too many times since
 the last time Reset() was called
 
+The returned BackOff implements encoding.BinaryMarshaler, json.Marshaler and
+their Unmarshaler counterparts, which succeed if b implements them too.
+
 Note: Implementation is not thread-safe.
 */
 func WithMaxRetries(b BackOff, max uint64) BackOff {
@@ -36,3 +46,85 @@ func (b *backOffTries) Reset() {
 	b.numTries = 0
 	b.delegate.Reset()
 }
+
+const triesStateVersion = 1
+
+// MarshalBinary implements encoding.BinaryMarshaler. The delegate policy
+// must implement encoding.BinaryMarshaler too.
+func (b *backOffTries) MarshalBinary() ([]byte, error) {
+	m, ok := b.delegate.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("backoff: %T does not implement encoding.BinaryMarshaler", b.delegate)
+	}
+	delegate, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 1+8+8, 1+8+8+len(delegate))
+	data[0] = triesStateVersion
+	binary.BigEndian.PutUint64(data[1:], b.maxTries)
+	binary.BigEndian.PutUint64(data[9:], b.numTries)
+	return append(data, delegate...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The delegate
+// policy is restored in place, so b must have been created by WithMaxRetries
+// with a delegate of the same type as the marshaled one.
+func (b *backOffTries) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+8+8 || data[0] != triesStateVersion {
+		return errors.New("backoff: invalid WithMaxRetries encoding")
+	}
+	u, ok := b.delegate.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("backoff: %T does not implement encoding.BinaryUnmarshaler", b.delegate)
+	}
+	if err := u.UnmarshalBinary(data[17:]); err != nil {
+		return err
+	}
+	b.maxTries = binary.BigEndian.Uint64(data[1:])
+	b.numTries = binary.BigEndian.Uint64(data[9:])
+	return nil
+}
+
+// triesState is the JSON form of backOffTries.
+type triesState struct {
+	MaxTries uint64          `json:"maxTries"`
+	NumTries uint64          `json:"numTries"`
+	Delegate json.RawMessage `json:"delegate"`
+}
+
+// MarshalJSON implements json.Marshaler. The delegate policy must
+// implement json.Marshaler too.
+func (b *backOffTries) MarshalJSON() ([]byte, error) {
+	m, ok := b.delegate.(json.Marshaler)
+	if !ok {
+		return nil, fmt.Errorf("backoff: %T does not implement json.Marshaler", b.delegate)
+	}
+	delegate, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(triesState{
+		MaxTries: b.maxTries,
+		NumTries: b.numTries,
+		Delegate: delegate,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See UnmarshalBinary.
+func (b *backOffTries) UnmarshalJSON(data []byte) error {
+	u, ok := b.delegate.(json.Unmarshaler)
+	if !ok {
+		return fmt.Errorf("backoff: %T does not implement json.Unmarshaler", b.delegate)
+	}
+	var s triesState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if err := u.UnmarshalJSON(s.Delegate); err != nil {
+		return err
+	}
+	b.maxTries = s.MaxTries
+	b.numTries = s.NumTries
+	return nil
+}
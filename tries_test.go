@@ -1,6 +1,8 @@
 package backoff
 
 import (
+	"encoding"
+	"encoding/json"
 	"errors"
 	"math/rand"
 	"testing"
@@ -61,3 +63,57 @@ func TestMaxTriesZero(t *testing.T) {
 		t.Errorf("operation is called %d times", called)
 	}
 }
+
+func TestMaxTriesMarshal(t *testing.T) {
+	bo := WithMaxRetries(NewExponentialBackOff(WithRandomizationFactor(0)), 3)
+	bo.NextBackOff()
+	bo.NextBackOff()
+
+	for _, codec := range []struct {
+		name      string
+		marshal   func(BackOff) ([]byte, error)
+		unmarshal func(BackOff, []byte) error
+	}{
+		{
+			"binary",
+			func(b BackOff) ([]byte, error) { return b.(encoding.BinaryMarshaler).MarshalBinary() },
+			func(b BackOff, data []byte) error { return b.(encoding.BinaryUnmarshaler).UnmarshalBinary(data) },
+		},
+		{
+			"json",
+			func(b BackOff) ([]byte, error) { return json.Marshal(b) },
+			func(b BackOff, data []byte) error { return json.Unmarshal(data, b) },
+		},
+	} {
+		data, err := codec.marshal(bo)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", codec.name, err)
+		}
+
+		restored := WithMaxRetries(&ExponentialBackOff{}, 0)
+		if err = codec.unmarshal(restored, data); err != nil {
+			t.Fatalf("%s: unexpected error: %s", codec.name, err)
+		}
+
+		assertEquals(t, 1125*time.Millisecond, restored.NextBackOff())
+		assertEquals(t, Stop, restored.NextBackOff())
+	}
+}
+
+func TestMaxTriesMarshalUnsupported(t *testing.T) {
+	bo := WithMaxRetries(&ZeroBackOff{}, 3)
+	if _, err := bo.(encoding.BinaryMarshaler).MarshalBinary(); err == nil {
+		t.Error("error is unexpectedly nil")
+	}
+
+	// A wrapper delegate would lose the state of the policy it wraps.
+	bo = WithMaxRetries(WithMinMax(NewExponentialBackOff(), 0, time.Minute), 5)
+	bo.NextBackOff()
+	if _, err := json.Marshal(bo); err == nil {
+		t.Error("error is unexpectedly nil")
+	}
+	data := []byte(`{"maxTries":5,"numTries":3,"delegate":{}}`)
+	if err := json.Unmarshal(data, bo); err == nil {
+		t.Error("error is unexpectedly nil")
+	}
+}
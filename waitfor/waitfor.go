@@ -0,0 +1,208 @@
+// Package waitfor waits for dependencies such as TCP servers, HTTP endpoints,
+// files and unix sockets to become ready, retrying probes with a backoff policy.
+//
+// Use it in integration tests and container entrypoints:
+//
+//	err := waitfor.Wait(ctx, backoff.NewExponentialBackOff(),
+//		waitfor.TCP("db:5432"),
+//		waitfor.HTTP("http://api:8080/healthz", http.StatusOK),
+//	)
+package waitfor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Probe checks whether a dependency is ready.
+type Probe struct {
+	// Name describes the dependency in errors.
+	Name string
+	// Check returns nil if the dependency is ready.
+	Check func(ctx context.Context) error
+}
+
+// ProbeError is the error of a probe that is not ready.
+type ProbeError struct {
+	Name string
+	Err  error
+}
+
+func (e ProbeError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+func (e ProbeError) Unwrap() error {
+	return e.Err
+}
+
+// NotReadyError is returned by Wait when some probes never became ready.
+type NotReadyError struct {
+	// Probes that were not ready on the last check.
+	Probes []ProbeError
+	// Err is the context error if the context is done, nil otherwise.
+	Err error
+}
+
+func (e *NotReadyError) Error() string {
+	var b strings.Builder
+	b.WriteString("waitfor: not ready: ")
+	for i, p := range e.Probes {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(p.Error())
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, " (%s)", e.Err)
+	}
+	return b.String()
+}
+
+func (e *NotReadyError) Unwrap() error {
+	return e.Err
+}
+
+// Wait checks probes until all of them are ready, b stops or ctx is done.
+// It returns a *NotReadyError listing the probes that never became ready.
+func Wait(ctx context.Context, b backoff.BackOff, probes ...Probe) error {
+	probe := All(probes...)
+
+	var last error
+	err := backoff.Poll(ctx, b, func(ctx context.Context) (bool, error) {
+		last = probe.Check(ctx)
+		return last == nil, nil
+	})
+	if err == nil {
+		return nil
+	}
+
+	notReady, ok := last.(*NotReadyError)
+	if !ok {
+		return err
+	}
+	notReady.Err = ctx.Err()
+	return notReady
+}
+
+// All returns a probe that is ready when all of probes are ready.
+func All(probes ...Probe) Probe {
+	return Probe{
+		Name: "all(" + names(probes) + ")",
+		Check: func(ctx context.Context) error {
+			var notReady NotReadyError
+			for _, p := range probes {
+				notReady.add(p, p.Check(ctx))
+			}
+			if len(notReady.Probes) > 0 {
+				return &notReady
+			}
+			return nil
+		},
+	}
+}
+
+// Any returns a probe that is ready when any of probes is ready.
+func Any(probes ...Probe) Probe {
+	return Probe{
+		Name: "any(" + names(probes) + ")",
+		Check: func(ctx context.Context) error {
+			var notReady NotReadyError
+			for _, p := range probes {
+				err := p.Check(ctx)
+				if err == nil {
+					return nil
+				}
+				notReady.add(p, err)
+			}
+			return &notReady
+		},
+	}
+}
+
+// add records err of p, flattening the errors of nested probes.
+func (e *NotReadyError) add(p Probe, err error) {
+	if err == nil {
+		return
+	}
+	if nested, ok := err.(*NotReadyError); ok {
+		e.Probes = append(e.Probes, nested.Probes...)
+		return
+	}
+	e.Probes = append(e.Probes, ProbeError{Name: p.Name, Err: err})
+}
+
+func names(probes []Probe) string {
+	s := make([]string, len(probes))
+	for i, p := range probes {
+		s[i] = p.Name
+	}
+	return strings.Join(s, ", ")
+}
+
+// TCP returns a probe that is ready when a TCP connection to addr succeeds.
+func TCP(addr string) Probe {
+	return dial("tcp", addr)
+}
+
+// UnixSocket returns a probe that is ready when a connection to the unix
+// socket at path succeeds.
+func UnixSocket(path string) Probe {
+	return dial("unix", path)
+}
+
+func dial(network, addr string) Probe {
+	return Probe{
+		Name: network + " " + addr,
+		Check: func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, network, addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// HTTP returns a probe that is ready when a GET request to url responds with
+// expectStatus. Any 2xx status is accepted if expectStatus is 0.
+func HTTP(url string, expectStatus int) Probe {
+	return Probe{
+		Name: "http " + url,
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+
+			if expectStatus == 0 && resp.StatusCode >= 200 && resp.StatusCode < 300 ||
+				resp.StatusCode == expectStatus {
+				return nil
+			}
+			return fmt.Errorf("unexpected status: %s", resp.Status)
+		},
+	}
+}
+
+// FileExists returns a probe that is ready when a file exists at path.
+func FileExists(path string) Probe {
+	return Probe{
+		Name: "file " + path,
+		Check: func(ctx context.Context) error {
+			_, err := os.Stat(path)
+			return err
+		},
+	}
+}
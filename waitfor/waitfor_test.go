@@ -0,0 +1,111 @@
+package waitfor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+func TestWait(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "s")
+	unix, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The file appears after a few checks.
+	file := filepath.Join(dir, "ready")
+	var checks int
+	fileProbe := FileExists(file)
+	check := fileProbe.Check
+	fileProbe.Check = func(ctx context.Context) error {
+		checks++
+		if checks == 3 {
+			if err := os.WriteFile(file, nil, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return check(ctx)
+	}
+
+	err = Wait(context.Background(), &backoff.ZeroBackOff{},
+		TCP(tcp.Addr().String()),
+		UnixSocket(socket),
+		HTTP(server.URL, 0),
+		fileProbe,
+	)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if checks != 3 {
+		t.Errorf("invalid number of checks: %d", checks)
+	}
+}
+
+func TestWaitNotReady(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	err := Wait(context.Background(), backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 2),
+		HTTP(server.URL, http.StatusOK),
+		Any(FileExists(missing), UnixSocket(missing)),
+	)
+
+	var notReady *NotReadyError
+	if !errors.As(err, &notReady) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notReady.Probes) != 3 {
+		t.Fatalf("invalid number of probes: %+v", notReady.Probes)
+	}
+	for i, name := range []string{"http " + server.URL, "file " + missing, "unix " + missing} {
+		if notReady.Probes[i].Name != name {
+			t.Errorf("got probe %s, expected %s", notReady.Probes[i].Name, name)
+		}
+	}
+	if !strings.Contains(err.Error(), "unexpected status: 503") {
+		t.Errorf("error does not describe the probe: %s", err)
+	}
+	if !errors.Is(notReady.Probes[1], os.ErrNotExist) {
+		t.Errorf("cause of the probe is not unwrapped: %v", notReady.Probes[1])
+	}
+}
+
+func TestWaitContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := Wait(ctx, backoff.NewConstantBackOff(time.Millisecond), FileExists(filepath.Join(t.TempDir(), "missing")))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var notReady *NotReadyError
+	if !errors.As(err, &notReady) || len(notReady.Probes) != 1 {
+		t.Errorf("probes are not reported: %v", err)
+	}
+}